@@ -1,159 +0,0 @@
--- MySQL dump 10.13  Distrib 8.0.18, for Linux (x86_64)
---
--- Host: localhost    Database: autorev
--- ------------------------------------------------------
--- Server version	8.0.18
-
-/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
-/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
-/*!40101 SET @OLD_COLLATION_CONNECTION=@@COLLATION_CONNECTION */;
-/*!50503 SET NAMES utf8mb4 */;
-/*!40103 SET @OLD_TIME_ZONE=@@TIME_ZONE */;
-/*!40103 SET TIME_ZONE='+00:00' */;
-/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
-/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
-/*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
-/*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
-
---
--- Table structure for table `tests`
---
-
-DROP TABLE IF EXISTS `tests`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `tests` (
-  `idTests` int(11) NOT NULL AUTO_INCREMENT,
-  `status` tinyint(4) DEFAULT NULL,
-  `ts_added` timestamp NULL DEFAULT NULL,
-  `ts_started` timestamp NULL DEFAULT NULL,
-  `ts_finished` timestamp NULL DEFAULT NULL,
-  `completeLog` blob,
-  `config` blob,
-  `fk_defaultConfig` int(10) unsigned NOT NULL,
-  PRIMARY KEY (`idTests`),
-  KEY `fk_defaultConfig` (`fk_defaultConfig`),
-  CONSTRAINT `fk_defaultConfig` FOREIGN KEY (`fk_defaultConfig`) REFERENCES `updDefaults` (`updId`)
-) ENGINE=InnoDB AUTO_INCREMENT=18 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Table structure for table `traceLog`
---
-
-DROP TABLE IF EXISTS `traceLog`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `traceLog` (
-  `idTraceLog` int(11) NOT NULL AUTO_INCREMENT,
-  `type` int(2) DEFAULT NULL,
-  `input` tinyint(4) DEFAULT NULL,
-  `address` bigint(20) DEFAULT NULL,
-  `value` bigint(20) unsigned NOT NULL,
-  `ip` bigint(20) DEFAULT NULL,
-  `accessSize` int(3) unsigned DEFAULT '0',
-  `fk_idTests` int(11) NOT NULL,
-  PRIMARY KEY (`idTraceLog`),
-  KEY `fk_idtests_id` (`fk_idTests`),
-  CONSTRAINT `fk_idtests` FOREIGN KEY (`fk_idTests`) REFERENCES `tests` (`idTests`)
-) ENGINE=InnoDB AUTO_INCREMENT=6013 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Dumping data for table `traceLog`
---
-
-LOCK TABLES `traceLog` WRITE;
-/*!40000 ALTER TABLE `traceLog` DISABLE KEYS */;
-/*!40000 ALTER TABLE `traceLog` ENABLE KEYS */;
-UNLOCK TABLES;
-
---
--- Table structure for table `updDefaults`
---
-
-DROP TABLE IF EXISTS `updDefaults`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `updDefaults` (
-  `updId` int(10) unsigned NOT NULL AUTO_INCREMENT,
-  `platformName` text NOT NULL,
-  `size` int(10) unsigned NOT NULL,
-  `configBlob` BLOB NOT NULL,
-  PRIMARY KEY (`updId`)
-);
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Dumping data for table `updDefaults`
---
-LOCK TABLES `updDefaults` WRITE;
-/*!40000 ALTER TABLE `updDefaults` DISABLE KEYS */;
-/*!40000 ALTER TABLE `updDefaults` ENABLE KEYS */;
-UNLOCK TABLES;
-
-
---
--- Table structure for table `updDefaults_qemu`
---
-
-DROP TABLE IF EXISTS `updDefaults_qemu`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `updDefaults_qemu` (
-  `qemuId` int(10) unsigned NOT NULL AUTO_INCREMENT,
-  `name` text NOT NULL,
-  `offset` varchar(6) NOT NULL DEFAULT '0x0000',
-  `defaultValue` bigint(20) unsigned NOT NULL DEFAULT '0',
-  `valueSize` int(10) unsigned NOT NULL DEFAULT '1',
-  PRIMARY KEY (`qemuId`)
-) ENGINE=InnoDB AUTO_INCREMENT=1413 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Dumping data for table `updDefaults_qemu`
---
-
-LOCK TABLES `updDefaults_qemu` WRITE;
-/*!40000 ALTER TABLE `updDefaults_qemu` DISABLE KEYS */;
-INSERT INTO `updDefaults_qemu` VALUES (1, 'config1', '0x0000', 0, 4), (2, 'config2', '0x0004', 0, 4), (3, 'config3', '0x0008', 0, 4), (4, 'config4', '0x000C', 0, 4), (5, 'config5', '0x0010', 0, 4), (6, 'config6', '0x0014', 0, 4);
-/*!40000 ALTER TABLE `updDefaults_qemu` ENABLE KEYS */;
-UNLOCK TABLES;
-
---
--- Table structure for table `updDefaults_kabylake`
---
-
-DROP TABLE IF EXISTS `updDefaults_kabylake`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `updDefaults_kabylake` (
-  `kabylakeId` int(10) unsigned NOT NULL AUTO_INCREMENT,
-  `name` text NOT NULL,
-  `offset` varchar(6) NOT NULL DEFAULT '0x0000',
-  `defaultValue` bigint(20) unsigned NOT NULL DEFAULT '0',
-  `valueSize` int(10) unsigned NOT NULL DEFAULT '1',
-  PRIMARY KEY (`kabylakeId`)
-) ENGINE=InnoDB AUTO_INCREMENT=1413 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
-/*!40101 SET character_set_client = @saved_cs_client */;
-
---
--- Dumping data for table `updDefaults_kabylake`
---
-
-LOCK TABLES `updDefaults_kabylake` WRITE;
-/*!40000 ALTER TABLE `updDefaults_kabylake` DISABLE KEYS */;
-INSERT INTO `updDefaults_kabylake` VALUES (18,'LogoPtr','0x0020',0,4),(19,'LogoSize','0x0024',0,4),(20,'GraphicsConfigPtr','0x0028',0,4),(21,'Device4Enable','0x002c',0,1),(22,'PchHdaEnable','0x002d',1,1),(23,'PchHdaDspEnable','0x002e',1,1),(24,'PchHdaIoBufferOwnership','0x002f',0,1),(25,'PchCio2Enable','0x0030',1,1),(26,'ScsEmmcEnabled','0x0031',1,1),(27,'ScsEmmcHs400Enabled','0x0032',1,1),(28,'ScsSdCardEnabled','0x0033',1,1),(29,'PchIshEnable','0x0034',1,1),(30,'ShowSpiController','0x0035',0,1),(31,'SpiFlashCfgLockDown','0x0036',1,1),(32,'MicrocodeRegionBase','0x0038',0,4),(33,'MicrocodeRegionSize','0x003c',0,4),(34,'TurboMode','0x0040',1,1),(35,'SataSalpSupport','0x0041',1,1),(36,'SataPortsEnable_0','0x0042',1,1),(37,'SataPortsEnable_1','0x0043',1,1),(38,'SataPortsEnable_2','0x0044',1,1),(39,'SataPortsEnable_3','0x0045',1,1),(40,'SataPortsEnable_4','0x0046',1,1),(41,'SataPortsEnable_5','0x0047',1,1),(42,'SataPortsEnable_6','0x0048',1,1),(43,'SataPortsEnable_7','0x0049',1,1),(44,'SataPortsDevSlp_0','0x004a',0,1),(45,'SataPortsDevSlp_1','0x004b',0,1),(46,'SataPortsDevSlp_2','0x004c',0,1),(47,'SataPortsDevSlp_3','0x004d',0,1),(48,'SataPortsDevSlp_4','0x004e',0,1),(49,'SataPortsDevSlp_5','0x004f',0,1),(50,'SataPortsDevSlp_6','0x0050',0,1),(51,'SataPortsDevSlp_7','0x0051',0,1),(52,'PortUsb20Enable_0','0x0052',1,1),(53,'PortUsb20Enable_1','0x0053',1,1),(54,'PortUsb20Enable_2','0x0054',1,1),(55,'PortUsb20Enable_3','0x0055',1,1),(56,'PortUsb20Enable_4','0x0056',1,1),(57,'PortUsb20Enable_5','0x0057',1,1),(58,'PortUsb20Enable_6','0x0058',1,1),(59,'PortUsb20Enable_7','0x0059',1,1),(60,'PortUsb20Enable_8','0x005a',1,1),(61,'PortUsb20Enable_9','0x005b',1,1),(62,'PortUsb20Enable_10','0x005c',1,1),(63,'PortUsb20Enable_11','0x005d',1,1),(64,'PortUsb20Enable_12','0x005e',1,1),(65,'PortUsb20Enable_13','0x005f',1,1),(66,'PortUsb20Enable_14','0x0060',1,1),(67,'PortUsb20Enable_15','0x0061',1,1),(68,'PortUsb30Enable_0','0x0062',1,1),(69,'PortUsb30Enable_1','0x0063',1,1),(70,'PortUsb30Enable_2','0x0064',1,1),(71,'PortUsb30Enable_3','0x0065',1,1),(72,'PortUsb30Enable_4','0x0066',1,1),(73,'PortUsb30Enable_5','0x0067',1,1),(74,'PortUsb30Enable_6','0x0068',1,1),(75,'PortUsb30Enable_7','0x0069',1,1),(76,'PortUsb30Enable_8','0x006a',1,1),(77,'PortUsb30Enable_9','0x006b',1,1),(78,'XdciEnable','0x006c',1,1),(79,'SsicPortEnable','0x006d',1,1),(80,'NumOfDevIntConfig','0x006f',0,1),(81,'DevIntConfigPtr','0x0070',0,4),(82,'SerialIoDevMode_0','0x0074',2,1),(83,'SerialIoDevMode_1','0x0075',2,1),(84,'SerialIoDevMode_2','0x0076',0,1),(85,'SerialIoDevMode_3','0x0077',0,1),(86,'SerialIoDevMode_4','0x0078',3,1),(87,'SerialIoDevMode_5','0x0079',0,1),(88,'SerialIoDevMode_6','0x007a',0,1),(89,'SerialIoDevMode_7','0x007b',0,1),(90,'SerialIoDevMode_8','0x007c',2,1),(91,'SerialIoDevMode_9','0x007d',0,1),(92,'SerialIoDevMode_10','0x007e',4,1),(93,'PxRcConfig_0','0x007f',11,1),(94,'PxRcConfig_1','0x0080',10,1),(95,'PxRcConfig_2','0x0081',11,1),(96,'PxRcConfig_3','0x0082',11,1),(97,'PxRcConfig_4','0x0083',11,1),(98,'PxRcConfig_5','0x0084',11,1),(99,'PxRcConfig_6','0x0085',11,1),(100,'PxRcConfig_7','0x0086',11,1),(101,'GpioIrqRoute','0x0087',14,1),(102,'SciIrqSelect','0x0088',9,1),(103,'TcoIrqSelect','0x0089',9,1),(104,'TcoIrqEnable','0x008a',0,1),(105,'PchHdaVerbTableEntryNum','0x008b',0,1),(106,'PchHdaVerbTablePtr','0x008c',0,4),(107,'SataEnable','0x0091',1,1),(108,'SataMode','0x0092',0,1),(109,'Usb2AfePetxiset_0','0x0093',7,1),(110,'Usb2AfePetxiset_1','0x0094',7,1),(111,'Usb2AfePetxiset_2','0x0095',7,1),(112,'Usb2AfePetxiset_3','0x0096',7,1),(113,'Usb2AfePetxiset_4','0x0097',7,1),(114,'Usb2AfePetxiset_5','0x0098',7,1),(115,'Usb2AfePetxiset_6','0x0099',7,1),(116,'Usb2AfePetxiset_7','0x009a',7,1),(117,'Usb2AfePetxiset_8','0x009b',7,1),(118,'Usb2AfePetxiset_9','0x009c',7,1),(119,'Usb2AfePetxiset_10','0x009d',7,1),(120,'Usb2AfePetxiset_11','0x009e',7,1),(121,'Usb2AfePetxiset_12','0x009f',7,1),(122,'Usb2AfePetxiset_13','0x00a0',7,1),(123,'Usb2AfePetxiset_14','0x00a1',7,1),(124,'Usb2AfePetxiset_15','0x00a2',7,1),(125,'Usb2AfeTxiset_0','0x00a3',0,1),(126,'Usb2AfeTxiset_1','0x00a4',0,1),(127,'Usb2AfeTxiset_2','0x00a5',0,1),(128,'Usb2AfeTxiset_3','0x00a6',0,1),(129,'Usb2AfeTxiset_4','0x00a7',0,1),(130,'Usb2AfeTxiset_5','0x00a8',0,1),(131,'Usb2AfeTxiset_6','0x00a9',0,1),(132,'Usb2AfeTxiset_7','0x00aa',0,1),(133,'Usb2AfeTxiset_8','0x00ab',5,1),(134,'Usb2AfeTxiset_9','0x00ac',0,1),(135,'Usb2AfeTxiset_10','0x00ad',0,1),(136,'Usb2AfeTxiset_11','0x00ae',0,1),(137,'Usb2AfeTxiset_12','0x00af',0,1),(138,'Usb2AfeTxiset_13','0x00b0',0,1),(139,'Usb2AfeTxiset_14','0x00b1',0,1),(140,'Usb2AfeTxiset_15','0x00b2',0,1),(141,'Usb2AfePredeemp_0','0x00b3',2,1),(142,'Usb2AfePredeemp_1','0x00b4',2,1),(143,'Usb2AfePredeemp_2','0x00b5',2,1),(144,'Usb2AfePredeemp_3','0x00b6',2,1),(145,'Usb2AfePredeemp_4','0x00b7',2,1),(146,'Usb2AfePredeemp_5','0x00b8',2,1),(147,'Usb2AfePredeemp_6','0x00b9',2,1),(148,'Usb2AfePredeemp_7','0x00ba',2,1),(149,'Usb2AfePredeemp_8','0x00bb',2,1),(150,'Usb2AfePredeemp_9','0x00bc',2,1),(151,'Usb2AfePredeemp_10','0x00bd',3,1),(152,'Usb2AfePredeemp_11','0x00be',3,1),(153,'Usb2AfePredeemp_12','0x00bf',3,1),(154,'Usb2AfePredeemp_13','0x00c0',3,1),(155,'Usb2AfePredeemp_14','0x00c1',3,1),(156,'Usb2AfePredeemp_15','0x00c2',3,1),(157,'Usb2AfePehalfbit_0','0x00c3',1,1),(158,'Usb2AfePehalfbit_1','0x00c4',1,1),(159,'Usb2AfePehalfbit_2','0x00c5',1,1),(160,'Usb2AfePehalfbit_3','0x00c6',1,1),(161,'Usb2AfePehalfbit_4','0x00c7',1,1),(162,'Usb2AfePehalfbit_5','0x00c8',1,1),(163,'Usb2AfePehalfbit_6','0x00c9',1,1),(164,'Usb2AfePehalfbit_7','0x00ca',1,1),(165,'Usb2AfePehalfbit_8','0x00cb',1,1),(166,'Usb2AfePehalfbit_9','0x00cc',1,1),(167,'Usb2AfePehalfbit_10','0x00cd',0,1),(168,'Usb2AfePehalfbit_11','0x00ce',0,1),(169,'Usb2AfePehalfbit_12','0x00cf',0,1),(170,'Usb2AfePehalfbit_13','0x00d0',0,1),(171,'Usb2AfePehalfbit_14','0x00d1',0,1),(172,'Usb2AfePehalfbit_15','0x00d2',0,1),(173,'Usb3HsioTxDeEmphEnable_0','0x00d3',0,1),(174,'Usb3HsioTxDeEmphEnable_1','0x00d4',0,1),(175,'Usb3HsioTxDeEmphEnable_2','0x00d5',0,1),(176,'Usb3HsioTxDeEmphEnable_3','0x00d6',0,1),(177,'Usb3HsioTxDeEmphEnable_4','0x00d7',0,1),(178,'Usb3HsioTxDeEmphEnable_5','0x00d8',0,1),(179,'Usb3HsioTxDeEmphEnable_6','0x00d9',0,1),(180,'Usb3HsioTxDeEmphEnable_7','0x00da',0,1),(181,'Usb3HsioTxDeEmphEnable_8','0x00db',0,1),(182,'Usb3HsioTxDeEmphEnable_9','0x00dc',0,1),(183,'Usb3HsioTxDeEmph_0','0x00dd',0,1),(184,'Usb3HsioTxDeEmph_1','0x00de',0,1),(185,'Usb3HsioTxDeEmph_2','0x00df',0,1),(186,'Usb3HsioTxDeEmph_3','0x00e0',0,1),(187,'Usb3HsioTxDeEmph_4','0x00e1',0,1),(188,'Usb3HsioTxDeEmph_5','0x00e2',0,1),(189,'Usb3HsioTxDeEmph_6','0x00e3',0,1),(190,'Usb3HsioTxDeEmph_7','0x00e4',0,1),(191,'Usb3HsioTxDeEmph_8','0x00e5',0,1),(192,'Usb3HsioTxDeEmph_9','0x00e6',0,1),(193,'Usb3HsioTxDownscaleAmpEnable_0','0x00e7',0,1),(194,'Usb3HsioTxDownscaleAmpEnable_1','0x00e8',0,1),(195,'Usb3HsioTxDownscaleAmpEnable_2','0x00e9',0,1),(196,'Usb3HsioTxDownscaleAmpEnable_3','0x00ea',0,1),(197,'Usb3HsioTxDownscaleAmpEnable_4','0x00eb',0,1),(198,'Usb3HsioTxDownscaleAmpEnable_5','0x00ec',0,1),(199,'Usb3HsioTxDownscaleAmpEnable_6','0x00ed',0,1),(200,'Usb3HsioTxDownscaleAmpEnable_7','0x00ee',0,1),(201,'Usb3HsioTxDownscaleAmpEnable_8','0x00ef',0,1),(202,'Usb3HsioTxDownscaleAmpEnable_9','0x00f0',0,1),(203,'Usb3HsioTxDownscaleAmp_0','0x00f1',0,1),(204,'Usb3HsioTxDownscaleAmp_1','0x00f2',0,1),(205,'Usb3HsioTxDownscaleAmp_2','0x00f3',0,1),(206,'Usb3HsioTxDownscaleAmp_3','0x00f4',0,1),(207,'Usb3HsioTxDownscaleAmp_4','0x00f5',0,1),(208,'Usb3HsioTxDownscaleAmp_5','0x00f6',0,1),(209,'Usb3HsioTxDownscaleAmp_6','0x00f7',0,1),(210,'Usb3HsioTxDownscaleAmp_7','0x00f8',0,1),(211,'Usb3HsioTxDownscaleAmp_8','0x00f9',0,1),(212,'Usb3HsioTxDownscaleAmp_9','0x00fa',0,1),(213,'PchLanEnable','0x00fb',1,1),(214,'DelayUsbPdoProgramming','0x00fc',0,1),(215,'PcieRpClkReqSupport_0','0x0114',1,1),(216,'PcieRpClkReqSupport_1','0x0115',0,1),(217,'PcieRpClkReqSupport_2','0x0116',0,1),(218,'PcieRpClkReqSupport_3','0x0117',0,1),(219,'PcieRpClkReqSupport_4','0x0118',1,1),(220,'PcieRpClkReqSupport_5','0x0119',1,1),(221,'PcieRpClkReqSupport_6','0x011a',0,1),(222,'PcieRpClkReqSupport_7','0x011b',0,1),(223,'PcieRpClkReqSupport_8','0x011c',1,1),(224,'PcieRpClkReqSupport_9','0x011d',1,1),(225,'PcieRpClkReqSupport_10','0x011e',0,1),(226,'PcieRpClkReqSupport_11','0x011f',0,1),(227,'PcieRpClkReqSupport_12','0x0120',0,1),(228,'PcieRpClkReqSupport_13','0x0121',0,1),(229,'PcieRpClkReqSupport_14','0x0122',0,1),(230,'PcieRpClkReqSupport_15','0x0123',0,1),(231,'PcieRpClkReqSupport_16','0x0124',0,1),(232,'PcieRpClkReqSupport_17','0x0125',0,1),(233,'PcieRpClkReqSupport_18','0x0126',0,1),(234,'PcieRpClkReqSupport_19','0x0127',0,1),(235,'PcieRpClkReqSupport_20','0x0128',0,1),(236,'PcieRpClkReqSupport_21','0x0129',0,1),(237,'PcieRpClkReqSupport_22','0x012a',0,1),(238,'PcieRpClkReqSupport_23','0x012b',0,1),(239,'PcieRpClkReqNumber_0','0x012c',2,1),(240,'PcieRpClkReqNumber_1','0x012d',0,1),(241,'PcieRpClkReqNumber_2','0x012e',0,1),(242,'PcieRpClkReqNumber_3','0x012f',0,1),(243,'PcieRpClkReqNumber_4','0x0130',3,1),(244,'PcieRpClkReqNumber_5','0x0131',1,1),(245,'PcieRpClkReqNumber_6','0x0132',0,1),(246,'PcieRpClkReqNumber_7','0x0133',0,1),(247,'PcieRpClkReqNumber_8','0x0134',5,1),(248,'PcieRpClkReqNumber_9','0x0135',4,1),(249,'PcieRpClkReqNumber_10','0x0136',0,1),(250,'PcieRpClkReqNumber_11','0x0137',0,1),(251,'PcieRpClkReqNumber_12','0x0138',0,1),(252,'PcieRpClkReqNumber_13','0x0139',0,1),(253,'PcieRpClkReqNumber_14','0x013a',0,1),(254,'PcieRpClkReqNumber_15','0x013b',0,1),(255,'PcieRpClkReqNumber_16','0x013c',0,1),(256,'PcieRpClkReqNumber_17','0x013d',0,1),(257,'PcieRpClkReqNumber_18','0x013e',0,1),(258,'PcieRpClkReqNumber_19','0x013f',0,1),(259,'PcieRpClkReqNumber_20','0x0140',0,1),(260,'PcieRpClkReqNumber_21','0x0141',0,1),(261,'PcieRpClkReqNumber_22','0x0142',0,1),(262,'PcieRpClkReqNumber_23','0x0143',0,1),(263,'Heci3Enabled','0x0149',0,1),(264,'AmtEnabled','0x0153',1,1),(265,'WatchDog','0x0154',0,1),(266,'AsfEnabled','0x0155',0,1),(267,'ManageabilityMode','0x0156',0,1),(268,'FwProgress','0x0157',0,1),(269,'WatchDogTimerOs','0x0158',0,2),(270,'WatchDogTimerBios','0x015a',0,2),(271,'AmtSolEnabled','0x015c',0,1),(272,'PcieRpClkSrcNumber_0','0x015d',2,1),(273,'PcieRpClkSrcNumber_1','0x015e',31,1),(274,'PcieRpClkSrcNumber_2','0x015f',31,1),(275,'PcieRpClkSrcNumber_3','0x0160',31,1),(276,'PcieRpClkSrcNumber_4','0x0161',3,1),(277,'PcieRpClkSrcNumber_5','0x0162',1,1),(278,'PcieRpClkSrcNumber_6','0x0163',31,1),(279,'PcieRpClkSrcNumber_7','0x0164',31,1),(280,'PcieRpClkSrcNumber_8','0x0165',5,1),(281,'PcieRpClkSrcNumber_9','0x0166',4,1),(282,'PcieRpClkSrcNumber_10','0x0167',31,1),(283,'PcieRpClkSrcNumber_11','0x0168',31,1),(284,'PcieRpClkSrcNumber_12','0x0169',31,1),(285,'PcieRpClkSrcNumber_13','0x016a',31,1),(286,'PcieRpClkSrcNumber_14','0x016b',31,1),(287,'PcieRpClkSrcNumber_15','0x016c',31,1),(288,'PcieRpClkSrcNumber_16','0x016d',31,1),(289,'PcieRpClkSrcNumber_17','0x016e',31,1),(290,'PcieRpClkSrcNumber_18','0x016f',31,1),(291,'PcieRpClkSrcNumber_19','0x0170',31,1),(292,'PcieRpClkSrcNumber_20','0x0171',31,1),(293,'PcieRpClkSrcNumber_21','0x0172',31,1),(294,'PcieRpClkSrcNumber_22','0x0173',31,1),(295,'PcieRpClkSrcNumber_23','0x0174',31,1),(296,'PcieRpForceClkDisableWhenRpDisable_0','0x0175',0,1),(297,'PcieRpForceClkDisableWhenRpDisable_1','0x0176',0,1),(298,'PcieRpForceClkDisableWhenRpDisable_2','0x0177',0,1),(299,'PcieRpForceClkDisableWhenRpDisable_3','0x0178',0,1),(300,'PcieRpForceClkDisableWhenRpDisable_4','0x0179',0,1),(301,'PcieRpForceClkDisableWhenRpDisable_5','0x017a',0,1),(302,'PcieRpForceClkDisableWhenRpDisable_6','0x017b',0,1),(303,'PcieRpForceClkDisableWhenRpDisable_7','0x017c',0,1),(304,'PcieRpForceClkDisableWhenRpDisable_8','0x017d',0,1),(305,'PcieRpForceClkDisableWhenRpDisable_9','0x017e',0,1),(306,'PcieRpForceClkDisableWhenRpDisable_10','0x017f',0,1),(307,'PcieRpForceClkDisableWhenRpDisable_11','0x0180',0,1),(308,'PcieRpForceClkDisableWhenRpDisable_12','0x0181',0,1),(309,'PcieRpForceClkDisableWhenRpDisable_13','0x0182',0,1),(310,'PcieRpForceClkDisableWhenRpDisable_14','0x0183',0,1),(311,'PcieRpForceClkDisableWhenRpDisable_15','0x0184',0,1),(312,'PcieRpForceClkDisableWhenRpDisable_16','0x0185',0,1),(313,'PcieRpForceClkDisableWhenRpDisable_17','0x0186',0,1),(314,'PcieRpForceClkDisableWhenRpDisable_18','0x0187',0,1),(315,'PcieRpForceClkDisableWhenRpDisable_19','0x0188',0,1),(316,'PcieRpForceClkDisableWhenRpDisable_20','0x0189',0,1),(317,'PcieRpForceClkDisableWhenRpDisable_21','0x018a',0,1),(318,'PcieRpForceClkDisableWhenRpDisable_22','0x018b',0,1),(319,'PcieRpForceClkDisableWhenRpDisable_23','0x018c',0,1),(320,'DefaultSvid','0x0200',34432,2),(321,'DefaultSid','0x0202',5408,2),(322,'CridEnable','0x0204',0,1),(323,'DmiAspm','0x0205',2,1),(324,'PegPhysicalSlotNumber_0','0x0206',256,2),(325,'PegPhysicalSlotNumber_1','0x0208',256,2),(326,'PegPhysicalSlotNumber_2','0x020a',256,2),(327,'PegDeEmphasis_0','0x020c',1,1),(328,'PegDeEmphasis_1','0x020d',1,1),(329,'PegDeEmphasis_2','0x020e',1,1),(330,'PegSlotPowerLimitValue_0','0x020f',75,1),(331,'PegSlotPowerLimitValue_1','0x0210',75,1),(332,'PegSlotPowerLimitValue_2','0x0211',75,1),(333,'PegSlotPowerLimitScale_0','0x0212',0,1),(334,'PegSlotPowerLimitScale_1','0x0213',0,1),(335,'PegSlotPowerLimitScale_2','0x0214',0,1),(336,'PavpEnable','0x0215',1,1),(337,'CdClock','0x0216',3,1),(338,'PeiGraphicsPeimInit','0x0217',1,1),(339,'SaImguEnable','0x0218',1,1),(340,'GmmEnable','0x0219',1,1),(341,'X2ApicOptOut','0x021a',0,1),(342,'VtdBaseAddress_0','0x021c',55806,4),(343,'VtdBaseAddress_1','0x0220',1104382,4),(344,'ProgramGtChickenBits','0x0224',14,1),(345,'SaPostMemProductionRsvd_0','0x0237',0,1),(346,'SaPostMemProductionRsvd_1','0x0238',0,1),(347,'SaPostMemProductionRsvd_2','0x0239',0,1),(348,'SaPostMemProductionRsvd_3','0x023a',0,1),(349,'SaPostMemProductionRsvd_4','0x023b',0,1),(350,'SaPostMemProductionRsvd_5','0x023c',0,1),(351,'SaPostMemProductionRsvd_6','0x023d',0,1),(352,'SaPostMemProductionRsvd_7','0x023e',0,1),(353,'SaPostMemProductionRsvd_8','0x023f',0,1),(354,'SaPostMemProductionRsvd_9','0x0240',0,1),(355,'SaPostMemProductionRsvd_10','0x0241',0,1),(356,'SaPostMemProductionRsvd_11','0x0242',0,1),(357,'SaPostMemProductionRsvd_12','0x0243',0,1),(358,'SaPostMemProductionRsvd_13','0x0244',0,1),(359,'SaPostMemProductionRsvd_14','0x0245',0,1),(360,'Psi3Enable_0','0x024e',1,1),(361,'Psi3Enable_1','0x024f',1,1),(362,'Psi3Enable_2','0x0250',1,1),(363,'Psi3Enable_3','0x0251',1,1),(364,'Psi3Enable_4','0x0252',1,1),(365,'Psi4Enable_0','0x0253',1,1),(366,'Psi4Enable_1','0x0254',1,1),(367,'Psi4Enable_2','0x0255',1,1),(368,'Psi4Enable_3','0x0256',1,1),(369,'Psi4Enable_4','0x0257',1,1),(370,'ImonSlope_0','0x0258',0,1),(371,'ImonSlope_1','0x0259',0,1),(372,'ImonSlope_2','0x025a',0,1),(373,'ImonSlope_3','0x025b',0,1),(374,'ImonSlope_4','0x025c',0,1),(375,'ImonOffset_0','0x025d',0,1),(376,'ImonOffset_1','0x025e',0,1),(377,'ImonOffset_2','0x025f',0,1),(378,'ImonOffset_3','0x0260',0,1),(379,'ImonOffset_4','0x0261',0,1),(380,'VrConfigEnable_0','0x0262',1,1),(381,'VrConfigEnable_1','0x0263',1,1),(382,'VrConfigEnable_2','0x0264',1,1),(383,'VrConfigEnable_3','0x0265',1,1),(384,'VrConfigEnable_4','0x0266',1,1),(385,'TdcEnable_0','0x0267',0,1),(386,'TdcEnable_1','0x0268',0,1),(387,'TdcEnable_2','0x0269',0,1),(388,'TdcEnable_3','0x026a',0,1),(389,'TdcEnable_4','0x026b',0,1),(390,'TdcTimeWindow_0','0x026c',1,1),(391,'TdcTimeWindow_1','0x026d',1,1),(392,'TdcTimeWindow_2','0x026e',1,1),(393,'TdcTimeWindow_3','0x026f',1,1),(394,'TdcTimeWindow_4','0x0270',1,1),(395,'TdcLock_0','0x0271',0,1),(396,'TdcLock_1','0x0272',0,1),(397,'TdcLock_2','0x0273',0,1),(398,'TdcLock_3','0x0274',0,1),(399,'TdcLock_4','0x0275',0,1),(400,'PsysSlope','0x0276',0,1),(401,'PsysOffset','0x0277',0,1),(402,'AcousticNoiseMitigation','0x0278',0,1),(403,'FastPkgCRampDisableIa','0x0279',0,1),(404,'SlowSlewRateForIa','0x027a',0,1),(405,'SlowSlewRateForGt','0x027b',0,1),(406,'SlowSlewRateForSa','0x027c',0,1),(407,'TdcPowerLimit_0','0x0286',0,2),(408,'TdcPowerLimit_1','0x0288',0,2),(409,'TdcPowerLimit_2','0x028a',0,2),(410,'TdcPowerLimit_3','0x028c',0,2),(411,'TdcPowerLimit_4','0x028e',0,2),(412,'VrPowerDeliveryDesign','0x0290',0,4),(413,'AcLoadline_0','0x0298',0,2),(414,'AcLoadline_1','0x029a',0,2),(415,'AcLoadline_2','0x029c',0,2),(416,'AcLoadline_3','0x029e',0,2),(417,'AcLoadline_4','0x02a0',0,2),(418,'DcLoadline_0','0x02a2',0,2),(419,'DcLoadline_1','0x02a4',0,2),(420,'DcLoadline_2','0x02a6',0,2),(421,'DcLoadline_3','0x02a8',0,2),(422,'DcLoadline_4','0x02aa',0,2),(423,'Psi1Threshold_0','0x02ac',0,2),(424,'Psi1Threshold_1','0x02ae',0,2),(425,'Psi1Threshold_2','0x02b0',0,2),(426,'Psi1Threshold_3','0x02b2',0,2),(427,'Psi1Threshold_4','0x02b4',0,2),(428,'Psi2Threshold_0','0x02b6',0,2),(429,'Psi2Threshold_1','0x02b8',0,2),(430,'Psi2Threshold_2','0x02ba',0,2),(431,'Psi2Threshold_3','0x02bc',0,2),(432,'Psi2Threshold_4','0x02be',0,2),(433,'Psi3Threshold_0','0x02c0',0,2),(434,'Psi3Threshold_1','0x02c2',0,2),(435,'Psi3Threshold_2','0x02c4',0,2),(436,'Psi3Threshold_3','0x02c6',0,2),(437,'Psi3Threshold_4','0x02c8',0,2),(438,'IccMax_0','0x02ca',0,2),(439,'IccMax_1','0x02cc',0,2),(440,'IccMax_2','0x02ce',0,2),(441,'IccMax_3','0x02d0',0,2),(442,'IccMax_4','0x02d2',0,2),(443,'VrVoltageLimit_0','0x02d4',0,2),(444,'VrVoltageLimit_1','0x02d6',0,2),(445,'VrVoltageLimit_2','0x02d8',0,2),(446,'VrVoltageLimit_3','0x02da',0,2),(447,'VrVoltageLimit_4','0x02dc',0,2),(448,'FastPkgCRampDisableGt','0x02df',0,1),(449,'FastPkgCRampDisableSa','0x02e0',0,1),(450,'SendVrMbxCmd','0x02e2',0,1),(451,'SendVrMbxCmd1','0x02e3',0,1),(452,'CpuS3ResumeMtrrData','0x02e4',0,4),(453,'MicrocodePatchAddress','0x02f0',0,8),(454,'CpuS3ResumeMtrrDataSize','0x02f8',0,2),(455,'PchSkyCamPortATermOvrEnable','0x02fb',0,1),(456,'PchSkyCamPortBTermOvrEnable','0x02fc',0,1),(457,'PchSkyCamPortCTermOvrEnable','0x02fd',0,1),(458,'PchSkyCamPortDTermOvrEnable','0x02fe',0,1),(459,'PchSkyCamPortATrimEnable','0x02ff',1,1),(460,'PchSkyCamPortBTrimEnable','0x0300',1,1),(461,'PchSkyCamPortCTrimEnable','0x0301',1,1),(462,'PchSkyCamPortDTrimEnable','0x0302',1,1),(463,'PchSkyCamPortACtleEnable','0x0303',0,1),(464,'PchSkyCamPortBCtleEnable','0x0304',0,1),(465,'PchSkyCamPortCDCtleEnable','0x0305',0,1),(466,'PchSkyCamPortACtleCapValue','0x0306',14,1),(467,'PchSkyCamPortBCtleCapValue','0x0307',14,1),(468,'PchSkyCamPortCDCtleCapValue','0x0308',14,1),(469,'PchSkyCamPortACtleResValue','0x0309',13,1),(470,'PchSkyCamPortBCtleResValue','0x030a',13,1),(471,'PchSkyCamPortCDCtleResValue','0x030b',13,1),(472,'PchSkyCamPortAClkTrimValue','0x030c',10,1),(473,'PchSkyCamPortBClkTrimValue','0x030d',10,1),(474,'PchSkyCamPortCClkTrimValue','0x030e',9,1),(475,'PchSkyCamPortDClkTrimValue','0x030f',10,1),(476,'PchSkyCamPortADataTrimValue','0x0310',48059,2),(477,'PchSkyCamPortBDataTrimValue','0x0312',48059,2),(478,'PchSkyCamPortCDDataTrimValue','0x0314',52428,2),(479,'PchDmiAspm','0x0316',1,1),(480,'PchPwrOptEnable','0x0317',0,1),(481,'PchWriteProtectionEnable_0','0x0318',0,1),(482,'PchWriteProtectionEnable_1','0x0319',0,1),(483,'PchWriteProtectionEnable_2','0x031a',0,1),(484,'PchWriteProtectionEnable_3','0x031b',0,1),(485,'PchWriteProtectionEnable_4','0x031c',0,1),(486,'PchReadProtectionEnable_0','0x031d',0,1),(487,'PchReadProtectionEnable_1','0x031e',0,1),(488,'PchReadProtectionEnable_2','0x031f',0,1),(489,'PchReadProtectionEnable_3','0x0320',0,1),(490,'PchReadProtectionEnable_4','0x0321',0,1),(491,'PchProtectedRangeLimit_0','0x0322',0,2),(492,'PchProtectedRangeLimit_1','0x0324',0,2),(493,'PchProtectedRangeLimit_2','0x0326',0,2),(494,'PchProtectedRangeLimit_3','0x0328',0,2),(495,'PchProtectedRangeLimit_4','0x032a',0,2),(496,'PchProtectedRangeBase_0','0x032c',0,2),(497,'PchProtectedRangeBase_1','0x032e',0,2),(498,'PchProtectedRangeBase_2','0x0330',0,2),(499,'PchProtectedRangeBase_3','0x0332',0,2),(500,'PchProtectedRangeBase_4','0x0334',0,2),(501,'PchHdaPme','0x0336',0,1),(502,'PchHdaIoBufferVoltage','0x0337',0,1),(503,'PchHdaVcType','0x0338',0,1),(504,'PchHdaLinkFrequency','0x0339',2,1),(505,'PchHdaIDispLinkFrequency','0x033a',4,1),(506,'PchHdaIDispLinkTmode','0x033b',0,1),(507,'PchHdaDspUaaCompliance','0x033c',0,1),(508,'PchHdaIDispCodecDisconnect','0x033d',0,1),(509,'PchHdaDspEndpointDmic','0x033e',2,1),(510,'PchHdaDspEndpointBluetooth','0x033f',0,1),(511,'PchHdaDspFeatureMask','0x0340',0,4),(512,'PchHdaDspPpModuleMask','0x0344',0,4),(513,'PchHdaDspEndpointI2s','0x0348',0,1),(514,'PchIoApicBdfValid','0x0349',0,1),(515,'PchIoApicBusNumber','0x034a',240,1),(516,'PchIoApicDeviceNumber','0x034b',31,1),(517,'PchIoApicFunctionNumber','0x034c',0,1),(518,'PchIoApicEntry24_119','0x034d',1,1),(519,'PchIoApicId','0x034e',2,1),(520,'PchIoApicRangeSelect','0x034f',0,1),(521,'PchIshSpiGpioAssign','0x0350',0,1),(522,'PchIshUart0GpioAssign','0x0351',0,1),(523,'PchIshUart1GpioAssign','0x0352',0,1),(524,'PchIshI2c0GpioAssign','0x0353',0,1),(525,'PchIshI2c1GpioAssign','0x0354',0,1),(526,'PchIshI2c2GpioAssign','0x0355',0,1),(527,'PchIshGp0GpioAssign','0x0356',0,1),(528,'PchIshGp1GpioAssign','0x0357',0,1),(529,'PchIshGp2GpioAssign','0x0358',0,1),(530,'PchIshGp3GpioAssign','0x0359',0,1),(531,'PchIshGp4GpioAssign','0x035a',0,1),(532,'PchIshGp5GpioAssign','0x035b',0,1),(533,'PchIshGp6GpioAssign','0x035c',0,1),(534,'PchIshGp7GpioAssign','0x035d',0,1),(535,'PchIshPdtUnlock','0x035e',0,1),(536,'PchLanLtrEnable','0x035f',1,1),(537,'PchLanK1OffEnable','0x0360',0,1),(538,'PchLanClkReqSupported','0x0361',0,1),(539,'PchLanClkReqNumber','0x0362',0,1),(540,'PchLockDownBiosLock','0x0363',0,1),(541,'PchLockDownSpiEiss','0x0364',1,1),(542,'PchCrid','0x0365',0,1),(543,'PchSubSystemVendorId','0x0366',34432,2),(544,'PchSubSystemId','0x0368',28786,2),(545,'PchLegacyIoLowLatency','0x036a',0,1),(546,'PcieRpHotPlug_0','0x0370',0,1),(547,'PcieRpHotPlug_1','0x0371',0,1),(548,'PcieRpHotPlug_2','0x0372',0,1),(549,'PcieRpHotPlug_3','0x0373',0,1),(550,'PcieRpHotPlug_4','0x0374',0,1),(551,'PcieRpHotPlug_5','0x0375',0,1),(552,'PcieRpHotPlug_6','0x0376',0,1),(553,'PcieRpHotPlug_7','0x0377',0,1),(554,'PcieRpHotPlug_8','0x0378',0,1),(555,'PcieRpHotPlug_9','0x0379',0,1),(556,'PcieRpHotPlug_10','0x037a',0,1),(557,'PcieRpHotPlug_11','0x037b',0,1),(558,'PcieRpHotPlug_12','0x037c',0,1),(559,'PcieRpHotPlug_13','0x037d',0,1),(560,'PcieRpHotPlug_14','0x037e',0,1),(561,'PcieRpHotPlug_15','0x037f',0,1),(562,'PcieRpHotPlug_16','0x0380',0,1),(563,'PcieRpHotPlug_17','0x0381',0,1),(564,'PcieRpHotPlug_18','0x0382',0,1),(565,'PcieRpHotPlug_19','0x0383',0,1),(566,'PcieRpHotPlug_20','0x0384',0,1),(567,'PcieRpHotPlug_21','0x0385',0,1),(568,'PcieRpHotPlug_22','0x0386',0,1),(569,'PcieRpHotPlug_23','0x0387',0,1),(570,'PcieRpPmSci_0','0x0388',1,1),(571,'PcieRpPmSci_1','0x0389',1,1),(572,'PcieRpPmSci_2','0x038a',1,1),(573,'PcieRpPmSci_3','0x038b',1,1),(574,'PcieRpPmSci_4','0x038c',1,1),(575,'PcieRpPmSci_5','0x038d',1,1),(576,'PcieRpPmSci_6','0x038e',1,1),(577,'PcieRpPmSci_7','0x038f',1,1),(578,'PcieRpPmSci_8','0x0390',1,1),(579,'PcieRpPmSci_9','0x0391',1,1),(580,'PcieRpPmSci_10','0x0392',1,1),(581,'PcieRpPmSci_11','0x0393',1,1),(582,'PcieRpPmSci_12','0x0394',1,1),(583,'PcieRpPmSci_13','0x0395',1,1),(584,'PcieRpPmSci_14','0x0396',1,1),(585,'PcieRpPmSci_15','0x0397',1,1),(586,'PcieRpPmSci_16','0x0398',1,1),(587,'PcieRpPmSci_17','0x0399',1,1),(588,'PcieRpPmSci_18','0x039a',1,1),(589,'PcieRpPmSci_19','0x039b',1,1),(590,'PcieRpPmSci_20','0x039c',1,1),(591,'PcieRpPmSci_21','0x039d',1,1),(592,'PcieRpPmSci_22','0x039e',1,1),(593,'PcieRpPmSci_23','0x039f',1,1),(594,'PcieRpExtSync_0','0x03a0',0,1),(595,'PcieRpExtSync_1','0x03a1',0,1),(596,'PcieRpExtSync_2','0x03a2',0,1),(597,'PcieRpExtSync_3','0x03a3',0,1),(598,'PcieRpExtSync_4','0x03a4',0,1),(599,'PcieRpExtSync_5','0x03a5',0,1),(600,'PcieRpExtSync_6','0x03a6',0,1),(601,'PcieRpExtSync_7','0x03a7',0,1),(602,'PcieRpExtSync_8','0x03a8',0,1),(603,'PcieRpExtSync_9','0x03a9',0,1),(604,'PcieRpExtSync_10','0x03aa',0,1),(605,'PcieRpExtSync_11','0x03ab',0,1),(606,'PcieRpExtSync_12','0x03ac',0,1),(607,'PcieRpExtSync_13','0x03ad',0,1),(608,'PcieRpExtSync_14','0x03ae',0,1),(609,'PcieRpExtSync_15','0x03af',0,1),(610,'PcieRpExtSync_16','0x03b0',0,1),(611,'PcieRpExtSync_17','0x03b1',0,1),(612,'PcieRpExtSync_18','0x03b2',0,1),(613,'PcieRpExtSync_19','0x03b3',0,1),(614,'PcieRpExtSync_20','0x03b4',0,1),(615,'PcieRpExtSync_21','0x03b5',0,1),(616,'PcieRpExtSync_22','0x03b6',0,1),(617,'PcieRpExtSync_23','0x03b7',0,1),(618,'PcieRpTransmitterHalfSwing_0','0x03b8',0,1),(619,'PcieRpTransmitterHalfSwing_1','0x03b9',0,1),(620,'PcieRpTransmitterHalfSwing_2','0x03ba',0,1),(621,'PcieRpTransmitterHalfSwing_3','0x03bb',0,1),(622,'PcieRpTransmitterHalfSwing_4','0x03bc',0,1),(623,'PcieRpTransmitterHalfSwing_5','0x03bd',0,1),(624,'PcieRpTransmitterHalfSwing_6','0x03be',0,1),(625,'PcieRpTransmitterHalfSwing_7','0x03bf',0,1),(626,'PcieRpTransmitterHalfSwing_8','0x03c0',0,1),(627,'PcieRpTransmitterHalfSwing_9','0x03c1',0,1),(628,'PcieRpTransmitterHalfSwing_10','0x03c2',0,1),(629,'PcieRpTransmitterHalfSwing_11','0x03c3',0,1),(630,'PcieRpTransmitterHalfSwing_12','0x03c4',0,1),(631,'PcieRpTransmitterHalfSwing_13','0x03c5',0,1),(632,'PcieRpTransmitterHalfSwing_14','0x03c6',0,1),(633,'PcieRpTransmitterHalfSwing_15','0x03c7',0,1),(634,'PcieRpTransmitterHalfSwing_16','0x03c8',0,1),(635,'PcieRpTransmitterHalfSwing_17','0x03c9',0,1),(636,'PcieRpTransmitterHalfSwing_18','0x03ca',0,1),(637,'PcieRpTransmitterHalfSwing_19','0x03cb',0,1),(638,'PcieRpTransmitterHalfSwing_20','0x03cc',0,1),(639,'PcieRpTransmitterHalfSwing_21','0x03cd',0,1),(640,'PcieRpTransmitterHalfSwing_22','0x03ce',0,1),(641,'PcieRpTransmitterHalfSwing_23','0x03cf',0,1),(642,'PcieRpClkReqDetect_0','0x03d0',0,1),(643,'PcieRpClkReqDetect_1','0x03d1',0,1),(644,'PcieRpClkReqDetect_2','0x03d2',0,1),(645,'PcieRpClkReqDetect_3','0x03d3',0,1),(646,'PcieRpClkReqDetect_4','0x03d4',0,1),(647,'PcieRpClkReqDetect_5','0x03d5',0,1),(648,'PcieRpClkReqDetect_6','0x03d6',0,1),(649,'PcieRpClkReqDetect_7','0x03d7',0,1),(650,'PcieRpClkReqDetect_8','0x03d8',0,1),(651,'PcieRpClkReqDetect_9','0x03d9',0,1),(652,'PcieRpClkReqDetect_10','0x03da',0,1),(653,'PcieRpClkReqDetect_11','0x03db',0,1),(654,'PcieRpClkReqDetect_12','0x03dc',0,1),(655,'PcieRpClkReqDetect_13','0x03dd',0,1),(656,'PcieRpClkReqDetect_14','0x03de',0,1),(657,'PcieRpClkReqDetect_15','0x03df',0,1),(658,'PcieRpClkReqDetect_16','0x03e0',0,1),(659,'PcieRpClkReqDetect_17','0x03e1',0,1),(660,'PcieRpClkReqDetect_18','0x03e2',0,1),(661,'PcieRpClkReqDetect_19','0x03e3',0,1),(662,'PcieRpClkReqDetect_20','0x03e4',0,1),(663,'PcieRpClkReqDetect_21','0x03e5',0,1),(664,'PcieRpClkReqDetect_22','0x03e6',0,1),(665,'PcieRpClkReqDetect_23','0x03e7',0,1),(666,'PcieRpAdvancedErrorReporting_0','0x03e8',0,1),(667,'PcieRpAdvancedErrorReporting_1','0x03e9',0,1),(668,'PcieRpAdvancedErrorReporting_2','0x03ea',0,1),(669,'PcieRpAdvancedErrorReporting_3','0x03eb',0,1),(670,'PcieRpAdvancedErrorReporting_4','0x03ec',0,1),(671,'PcieRpAdvancedErrorReporting_5','0x03ed',0,1),(672,'PcieRpAdvancedErrorReporting_6','0x03ee',0,1),(673,'PcieRpAdvancedErrorReporting_7','0x03ef',0,1),(674,'PcieRpAdvancedErrorReporting_8','0x03f0',0,1),(675,'PcieRpAdvancedErrorReporting_9','0x03f1',0,1),(676,'PcieRpAdvancedErrorReporting_10','0x03f2',0,1),(677,'PcieRpAdvancedErrorReporting_11','0x03f3',0,1),(678,'PcieRpAdvancedErrorReporting_12','0x03f4',0,1),(679,'PcieRpAdvancedErrorReporting_13','0x03f5',0,1),(680,'PcieRpAdvancedErrorReporting_14','0x03f6',0,1),(681,'PcieRpAdvancedErrorReporting_15','0x03f7',0,1),(682,'PcieRpAdvancedErrorReporting_16','0x03f8',0,1),(683,'PcieRpAdvancedErrorReporting_17','0x03f9',0,1),(684,'PcieRpAdvancedErrorReporting_18','0x03fa',0,1),(685,'PcieRpAdvancedErrorReporting_19','0x03fb',0,1),(686,'PcieRpAdvancedErrorReporting_20','0x03fc',0,1),(687,'PcieRpAdvancedErrorReporting_21','0x03fd',0,1),(688,'PcieRpAdvancedErrorReporting_22','0x03fe',0,1),(689,'PcieRpAdvancedErrorReporting_23','0x03ff',0,1),(690,'PcieRpUnsupportedRequestReport_0','0x0400',0,1),(691,'PcieRpUnsupportedRequestReport_1','0x0401',0,1),(692,'PcieRpUnsupportedRequestReport_2','0x0402',0,1),(693,'PcieRpUnsupportedRequestReport_3','0x0403',0,1),(694,'PcieRpUnsupportedRequestReport_4','0x0404',0,1),(695,'PcieRpUnsupportedRequestReport_5','0x0405',0,1),(696,'PcieRpUnsupportedRequestReport_6','0x0406',0,1),(697,'PcieRpUnsupportedRequestReport_7','0x0407',0,1),(698,'PcieRpUnsupportedRequestReport_8','0x0408',0,1),(699,'PcieRpUnsupportedRequestReport_9','0x0409',0,1),(700,'PcieRpUnsupportedRequestReport_10','0x040a',0,1),(701,'PcieRpUnsupportedRequestReport_11','0x040b',0,1),(702,'PcieRpUnsupportedRequestReport_12','0x040c',0,1),(703,'PcieRpUnsupportedRequestReport_13','0x040d',0,1),(704,'PcieRpUnsupportedRequestReport_14','0x040e',0,1),(705,'PcieRpUnsupportedRequestReport_15','0x040f',0,1),(706,'PcieRpUnsupportedRequestReport_16','0x0410',0,1),(707,'PcieRpUnsupportedRequestReport_17','0x0411',0,1),(708,'PcieRpUnsupportedRequestReport_18','0x0412',0,1),(709,'PcieRpUnsupportedRequestReport_19','0x0413',0,1),(710,'PcieRpUnsupportedRequestReport_20','0x0414',0,1),(711,'PcieRpUnsupportedRequestReport_21','0x0415',0,1),(712,'PcieRpUnsupportedRequestReport_22','0x0416',0,1),(713,'PcieRpUnsupportedRequestReport_23','0x0417',0,1),(714,'PcieRpFatalErrorReport_0','0x0418',0,1),(715,'PcieRpFatalErrorReport_1','0x0419',0,1),(716,'PcieRpFatalErrorReport_2','0x041a',0,1),(717,'PcieRpFatalErrorReport_3','0x041b',0,1),(718,'PcieRpFatalErrorReport_4','0x041c',0,1),(719,'PcieRpFatalErrorReport_5','0x041d',0,1),(720,'PcieRpFatalErrorReport_6','0x041e',0,1),(721,'PcieRpFatalErrorReport_7','0x041f',0,1),(722,'PcieRpFatalErrorReport_8','0x0420',0,1),(723,'PcieRpFatalErrorReport_9','0x0421',0,1),(724,'PcieRpFatalErrorReport_10','0x0422',0,1),(725,'PcieRpFatalErrorReport_11','0x0423',0,1),(726,'PcieRpFatalErrorReport_12','0x0424',0,1),(727,'PcieRpFatalErrorReport_13','0x0425',0,1),(728,'PcieRpFatalErrorReport_14','0x0426',0,1),(729,'PcieRpFatalErrorReport_15','0x0427',0,1),(730,'PcieRpFatalErrorReport_16','0x0428',0,1),(731,'PcieRpFatalErrorReport_17','0x0429',0,1),(732,'PcieRpFatalErrorReport_18','0x042a',0,1),(733,'PcieRpFatalErrorReport_19','0x042b',0,1),(734,'PcieRpFatalErrorReport_20','0x042c',0,1),(735,'PcieRpFatalErrorReport_21','0x042d',0,1),(736,'PcieRpFatalErrorReport_22','0x042e',0,1),(737,'PcieRpFatalErrorReport_23','0x042f',0,1),(738,'PcieRpNoFatalErrorReport_0','0x0430',0,1),(739,'PcieRpNoFatalErrorReport_1','0x0431',0,1),(740,'PcieRpNoFatalErrorReport_2','0x0432',0,1),(741,'PcieRpNoFatalErrorReport_3','0x0433',0,1),(742,'PcieRpNoFatalErrorReport_4','0x0434',0,1),(743,'PcieRpNoFatalErrorReport_5','0x0435',0,1),(744,'PcieRpNoFatalErrorReport_6','0x0436',0,1),(745,'PcieRpNoFatalErrorReport_7','0x0437',0,1),(746,'PcieRpNoFatalErrorReport_8','0x0438',0,1),(747,'PcieRpNoFatalErrorReport_9','0x0439',0,1),(748,'PcieRpNoFatalErrorReport_10','0x043a',0,1),(749,'PcieRpNoFatalErrorReport_11','0x043b',0,1),(750,'PcieRpNoFatalErrorReport_12','0x043c',0,1),(751,'PcieRpNoFatalErrorReport_13','0x043d',0,1),(752,'PcieRpNoFatalErrorReport_14','0x043e',0,1),(753,'PcieRpNoFatalErrorReport_15','0x043f',0,1),(754,'PcieRpNoFatalErrorReport_16','0x0440',0,1),(755,'PcieRpNoFatalErrorReport_17','0x0441',0,1),(756,'PcieRpNoFatalErrorReport_18','0x0442',0,1),(757,'PcieRpNoFatalErrorReport_19','0x0443',0,1),(758,'PcieRpNoFatalErrorReport_20','0x0444',0,1),(759,'PcieRpNoFatalErrorReport_21','0x0445',0,1),(760,'PcieRpNoFatalErrorReport_22','0x0446',0,1),(761,'PcieRpNoFatalErrorReport_23','0x0447',0,1),(762,'PcieRpCorrectableErrorReport_0','0x0448',0,1),(763,'PcieRpCorrectableErrorReport_1','0x0449',0,1),(764,'PcieRpCorrectableErrorReport_2','0x044a',0,1),(765,'PcieRpCorrectableErrorReport_3','0x044b',0,1),(766,'PcieRpCorrectableErrorReport_4','0x044c',0,1),(767,'PcieRpCorrectableErrorReport_5','0x044d',0,1),(768,'PcieRpCorrectableErrorReport_6','0x044e',0,1),(769,'PcieRpCorrectableErrorReport_7','0x044f',0,1),(770,'PcieRpCorrectableErrorReport_8','0x0450',0,1),(771,'PcieRpCorrectableErrorReport_9','0x0451',0,1),(772,'PcieRpCorrectableErrorReport_10','0x0452',0,1),(773,'PcieRpCorrectableErrorReport_11','0x0453',0,1),(774,'PcieRpCorrectableErrorReport_12','0x0454',0,1),(775,'PcieRpCorrectableErrorReport_13','0x0455',0,1),(776,'PcieRpCorrectableErrorReport_14','0x0456',0,1),(777,'PcieRpCorrectableErrorReport_15','0x0457',0,1),(778,'PcieRpCorrectableErrorReport_16','0x0458',0,1),(779,'PcieRpCorrectableErrorReport_17','0x0459',0,1),(780,'PcieRpCorrectableErrorReport_18','0x045a',0,1),(781,'PcieRpCorrectableErrorReport_19','0x045b',0,1),(782,'PcieRpCorrectableErrorReport_20','0x045c',0,1),(783,'PcieRpCorrectableErrorReport_21','0x045d',0,1),(784,'PcieRpCorrectableErrorReport_22','0x045e',0,1),(785,'PcieRpCorrectableErrorReport_23','0x045f',0,1),(786,'PcieRpSystemErrorOnFatalError_0','0x0460',0,1),(787,'PcieRpSystemErrorOnFatalError_1','0x0461',0,1),(788,'PcieRpSystemErrorOnFatalError_2','0x0462',0,1),(789,'PcieRpSystemErrorOnFatalError_3','0x0463',0,1),(790,'PcieRpSystemErrorOnFatalError_4','0x0464',0,1),(791,'PcieRpSystemErrorOnFatalError_5','0x0465',0,1),(792,'PcieRpSystemErrorOnFatalError_6','0x0466',0,1),(793,'PcieRpSystemErrorOnFatalError_7','0x0467',0,1),(794,'PcieRpSystemErrorOnFatalError_8','0x0468',0,1),(795,'PcieRpSystemErrorOnFatalError_9','0x0469',0,1),(796,'PcieRpSystemErrorOnFatalError_10','0x046a',0,1),(797,'PcieRpSystemErrorOnFatalError_11','0x046b',0,1),(798,'PcieRpSystemErrorOnFatalError_12','0x046c',0,1),(799,'PcieRpSystemErrorOnFatalError_13','0x046d',0,1),(800,'PcieRpSystemErrorOnFatalError_14','0x046e',0,1),(801,'PcieRpSystemErrorOnFatalError_15','0x046f',0,1),(802,'PcieRpSystemErrorOnFatalError_16','0x0470',0,1),(803,'PcieRpSystemErrorOnFatalError_17','0x0471',0,1),(804,'PcieRpSystemErrorOnFatalError_18','0x0472',0,1),(805,'PcieRpSystemErrorOnFatalError_19','0x0473',0,1),(806,'PcieRpSystemErrorOnFatalError_20','0x0474',0,1),(807,'PcieRpSystemErrorOnFatalError_21','0x0475',0,1),(808,'PcieRpSystemErrorOnFatalError_22','0x0476',0,1),(809,'PcieRpSystemErrorOnFatalError_23','0x0477',0,1),(810,'PcieRpSystemErrorOnNonFatalError_0','0x0478',0,1),(811,'PcieRpSystemErrorOnNonFatalError_1','0x0479',0,1),(812,'PcieRpSystemErrorOnNonFatalError_2','0x047a',0,1),(813,'PcieRpSystemErrorOnNonFatalError_3','0x047b',0,1),(814,'PcieRpSystemErrorOnNonFatalError_4','0x047c',0,1),(815,'PcieRpSystemErrorOnNonFatalError_5','0x047d',0,1),(816,'PcieRpSystemErrorOnNonFatalError_6','0x047e',0,1),(817,'PcieRpSystemErrorOnNonFatalError_7','0x047f',0,1),(818,'PcieRpSystemErrorOnNonFatalError_8','0x0480',0,1),(819,'PcieRpSystemErrorOnNonFatalError_9','0x0481',0,1),(820,'PcieRpSystemErrorOnNonFatalError_10','0x0482',0,1),(821,'PcieRpSystemErrorOnNonFatalError_11','0x0483',0,1),(822,'PcieRpSystemErrorOnNonFatalError_12','0x0484',0,1),(823,'PcieRpSystemErrorOnNonFatalError_13','0x0485',0,1),(824,'PcieRpSystemErrorOnNonFatalError_14','0x0486',0,1),(825,'PcieRpSystemErrorOnNonFatalError_15','0x0487',0,1),(826,'PcieRpSystemErrorOnNonFatalError_16','0x0488',0,1),(827,'PcieRpSystemErrorOnNonFatalError_17','0x0489',0,1),(828,'PcieRpSystemErrorOnNonFatalError_18','0x048a',0,1),(829,'PcieRpSystemErrorOnNonFatalError_19','0x048b',0,1),(830,'PcieRpSystemErrorOnNonFatalError_20','0x048c',0,1),(831,'PcieRpSystemErrorOnNonFatalError_21','0x048d',0,1),(832,'PcieRpSystemErrorOnNonFatalError_22','0x048e',0,1),(833,'PcieRpSystemErrorOnNonFatalError_23','0x048f',0,1),(834,'PcieRpSystemErrorOnCorrectableError_0','0x0490',0,1),(835,'PcieRpSystemErrorOnCorrectableError_1','0x0491',0,1),(836,'PcieRpSystemErrorOnCorrectableError_2','0x0492',0,1),(837,'PcieRpSystemErrorOnCorrectableError_3','0x0493',0,1),(838,'PcieRpSystemErrorOnCorrectableError_4','0x0494',0,1),(839,'PcieRpSystemErrorOnCorrectableError_5','0x0495',0,1),(840,'PcieRpSystemErrorOnCorrectableError_6','0x0496',0,1),(841,'PcieRpSystemErrorOnCorrectableError_7','0x0497',0,1),(842,'PcieRpSystemErrorOnCorrectableError_8','0x0498',0,1),(843,'PcieRpSystemErrorOnCorrectableError_9','0x0499',0,1),(844,'PcieRpSystemErrorOnCorrectableError_10','0x049a',0,1),(845,'PcieRpSystemErrorOnCorrectableError_11','0x049b',0,1),(846,'PcieRpSystemErrorOnCorrectableError_12','0x049c',0,1),(847,'PcieRpSystemErrorOnCorrectableError_13','0x049d',0,1),(848,'PcieRpSystemErrorOnCorrectableError_14','0x049e',0,1),(849,'PcieRpSystemErrorOnCorrectableError_15','0x049f',0,1),(850,'PcieRpSystemErrorOnCorrectableError_16','0x04a0',0,1),(851,'PcieRpSystemErrorOnCorrectableError_17','0x04a1',0,1),(852,'PcieRpSystemErrorOnCorrectableError_18','0x04a2',0,1),(853,'PcieRpSystemErrorOnCorrectableError_19','0x04a3',0,1),(854,'PcieRpSystemErrorOnCorrectableError_20','0x04a4',0,1),(855,'PcieRpSystemErrorOnCorrectableError_21','0x04a5',0,1),(856,'PcieRpSystemErrorOnCorrectableError_22','0x04a6',0,1),(857,'PcieRpSystemErrorOnCorrectableError_23','0x04a7',0,1),(858,'PcieRpMaxPayload_0','0x04a8',0,1),(859,'PcieRpMaxPayload_1','0x04a9',0,1),(860,'PcieRpMaxPayload_2','0x04aa',0,1),(861,'PcieRpMaxPayload_3','0x04ab',0,1),(862,'PcieRpMaxPayload_4','0x04ac',0,1),(863,'PcieRpMaxPayload_5','0x04ad',0,1),(864,'PcieRpMaxPayload_6','0x04ae',0,1),(865,'PcieRpMaxPayload_7','0x04af',0,1),(866,'PcieRpMaxPayload_8','0x04b0',0,1),(867,'PcieRpMaxPayload_9','0x04b1',0,1),(868,'PcieRpMaxPayload_10','0x04b2',0,1),(869,'PcieRpMaxPayload_11','0x04b3',0,1),(870,'PcieRpMaxPayload_12','0x04b4',0,1),(871,'PcieRpMaxPayload_13','0x04b5',0,1),(872,'PcieRpMaxPayload_14','0x04b6',0,1),(873,'PcieRpMaxPayload_15','0x04b7',0,1),(874,'PcieRpMaxPayload_16','0x04b8',0,1),(875,'PcieRpMaxPayload_17','0x04b9',0,1),(876,'PcieRpMaxPayload_18','0x04ba',0,1),(877,'PcieRpMaxPayload_19','0x04bb',0,1),(878,'PcieRpMaxPayload_20','0x04bc',0,1),(879,'PcieRpMaxPayload_21','0x04bd',0,1),(880,'PcieRpMaxPayload_22','0x04be',0,1),(881,'PcieRpMaxPayload_23','0x04bf',0,1),(882,'PcieRpDeviceResetPadActiveHigh_0','0x04c0',0,1),(883,'PcieRpDeviceResetPadActiveHigh_1','0x04c1',0,1),(884,'PcieRpDeviceResetPadActiveHigh_2','0x04c2',0,1),(885,'PcieRpDeviceResetPadActiveHigh_3','0x04c3',0,1),(886,'PcieRpDeviceResetPadActiveHigh_4','0x04c4',0,1),(887,'PcieRpDeviceResetPadActiveHigh_5','0x04c5',0,1),(888,'PcieRpDeviceResetPadActiveHigh_6','0x04c6',0,1),(889,'PcieRpDeviceResetPadActiveHigh_7','0x04c7',0,1),(890,'PcieRpDeviceResetPadActiveHigh_8','0x04c8',0,1),(891,'PcieRpDeviceResetPadActiveHigh_9','0x04c9',0,1),(892,'PcieRpDeviceResetPadActiveHigh_10','0x04ca',0,1),(893,'PcieRpDeviceResetPadActiveHigh_11','0x04cb',0,1),(894,'PcieRpDeviceResetPadActiveHigh_12','0x04cc',0,1),(895,'PcieRpDeviceResetPadActiveHigh_13','0x04cd',0,1),(896,'PcieRpDeviceResetPadActiveHigh_14','0x04ce',0,1),(897,'PcieRpDeviceResetPadActiveHigh_15','0x04cf',0,1),(898,'PcieRpDeviceResetPadActiveHigh_16','0x04d0',0,1),(899,'PcieRpDeviceResetPadActiveHigh_17','0x04d1',0,1),(900,'PcieRpDeviceResetPadActiveHigh_18','0x04d2',0,1),(901,'PcieRpDeviceResetPadActiveHigh_19','0x04d3',0,1),(902,'PcieRpDeviceResetPadActiveHigh_20','0x04d4',0,1),(903,'PcieRpDeviceResetPadActiveHigh_21','0x04d5',0,1),(904,'PcieRpDeviceResetPadActiveHigh_22','0x04d6',0,1),(905,'PcieRpDeviceResetPadActiveHigh_23','0x04d7',0,1),(906,'PcieRpPcieSpeed_0','0x04d8',0,1),(907,'PcieRpPcieSpeed_1','0x04d9',0,1),(908,'PcieRpPcieSpeed_2','0x04da',0,1),(909,'PcieRpPcieSpeed_3','0x04db',0,1),(910,'PcieRpPcieSpeed_4','0x04dc',0,1),(911,'PcieRpPcieSpeed_5','0x04dd',0,1),(912,'PcieRpPcieSpeed_6','0x04de',0,1),(913,'PcieRpPcieSpeed_7','0x04df',0,1),(914,'PcieRpPcieSpeed_8','0x04e0',0,1),(915,'PcieRpPcieSpeed_9','0x04e1',0,1),(916,'PcieRpPcieSpeed_10','0x04e2',0,1),(917,'PcieRpPcieSpeed_11','0x04e3',0,1),(918,'PcieRpPcieSpeed_12','0x04e4',0,1),(919,'PcieRpPcieSpeed_13','0x04e5',0,1),(920,'PcieRpPcieSpeed_14','0x04e6',0,1),(921,'PcieRpPcieSpeed_15','0x04e7',0,1),(922,'PcieRpPcieSpeed_16','0x04e8',0,1),(923,'PcieRpPcieSpeed_17','0x04e9',0,1),(924,'PcieRpPcieSpeed_18','0x04ea',0,1),(925,'PcieRpPcieSpeed_19','0x04eb',0,1),(926,'PcieRpPcieSpeed_20','0x04ec',0,1),(927,'PcieRpPcieSpeed_21','0x04ed',0,1),(928,'PcieRpPcieSpeed_22','0x04ee',0,1),(929,'PcieRpPcieSpeed_23','0x04ef',0,1),(930,'PcieRpGen3EqPh3Method_0','0x04f0',0,1),(931,'PcieRpGen3EqPh3Method_1','0x04f1',0,1),(932,'PcieRpGen3EqPh3Method_2','0x04f2',0,1),(933,'PcieRpGen3EqPh3Method_3','0x04f3',0,1),(934,'PcieRpGen3EqPh3Method_4','0x04f4',0,1),(935,'PcieRpGen3EqPh3Method_5','0x04f5',0,1),(936,'PcieRpGen3EqPh3Method_6','0x04f6',0,1),(937,'PcieRpGen3EqPh3Method_7','0x04f7',0,1),(938,'PcieRpGen3EqPh3Method_8','0x04f8',0,1),(939,'PcieRpGen3EqPh3Method_9','0x04f9',0,1),(940,'PcieRpGen3EqPh3Method_10','0x04fa',0,1),(941,'PcieRpGen3EqPh3Method_11','0x04fb',0,1),(942,'PcieRpGen3EqPh3Method_12','0x04fc',0,1),(943,'PcieRpGen3EqPh3Method_13','0x04fd',0,1),(944,'PcieRpGen3EqPh3Method_14','0x04fe',0,1),(945,'PcieRpGen3EqPh3Method_15','0x04ff',0,1),(946,'PcieRpGen3EqPh3Method_16','0x0500',0,1),(947,'PcieRpGen3EqPh3Method_17','0x0501',0,1),(948,'PcieRpGen3EqPh3Method_18','0x0502',0,1),(949,'PcieRpGen3EqPh3Method_19','0x0503',0,1),(950,'PcieRpGen3EqPh3Method_20','0x0504',0,1),(951,'PcieRpGen3EqPh3Method_21','0x0505',0,1),(952,'PcieRpGen3EqPh3Method_22','0x0506',0,1),(953,'PcieRpGen3EqPh3Method_23','0x0507',0,1),(954,'PcieRpPhysicalSlotNumber_0','0x0508',0,1),(955,'PcieRpPhysicalSlotNumber_1','0x0509',1,1),(956,'PcieRpPhysicalSlotNumber_2','0x050a',2,1),(957,'PcieRpPhysicalSlotNumber_3','0x050b',3,1),(958,'PcieRpPhysicalSlotNumber_4','0x050c',4,1),(959,'PcieRpPhysicalSlotNumber_5','0x050d',5,1),(960,'PcieRpPhysicalSlotNumber_6','0x050e',6,1),(961,'PcieRpPhysicalSlotNumber_7','0x050f',7,1),(962,'PcieRpPhysicalSlotNumber_8','0x0510',8,1),(963,'PcieRpPhysicalSlotNumber_9','0x0511',9,1),(964,'PcieRpPhysicalSlotNumber_10','0x0512',10,1),(965,'PcieRpPhysicalSlotNumber_11','0x0513',11,1),(966,'PcieRpPhysicalSlotNumber_12','0x0514',12,1),(967,'PcieRpPhysicalSlotNumber_13','0x0515',13,1),(968,'PcieRpPhysicalSlotNumber_14','0x0516',14,1),(969,'PcieRpPhysicalSlotNumber_15','0x0517',15,1),(970,'PcieRpPhysicalSlotNumber_16','0x0518',16,1),(971,'PcieRpPhysicalSlotNumber_17','0x0519',17,1),(972,'PcieRpPhysicalSlotNumber_18','0x051a',18,1),(973,'PcieRpPhysicalSlotNumber_19','0x051b',19,1),(974,'PcieRpPhysicalSlotNumber_20','0x051c',20,1),(975,'PcieRpPhysicalSlotNumber_21','0x051d',21,1),(976,'PcieRpPhysicalSlotNumber_22','0x051e',22,1),(977,'PcieRpPhysicalSlotNumber_23','0x051f',23,1),(978,'PcieRpCompletionTimeout_0','0x0520',0,1),(979,'PcieRpCompletionTimeout_1','0x0521',0,1),(980,'PcieRpCompletionTimeout_2','0x0522',0,1),(981,'PcieRpCompletionTimeout_3','0x0523',0,1),(982,'PcieRpCompletionTimeout_4','0x0524',0,1),(983,'PcieRpCompletionTimeout_5','0x0525',0,1),(984,'PcieRpCompletionTimeout_6','0x0526',0,1),(985,'PcieRpCompletionTimeout_7','0x0527',0,1),(986,'PcieRpCompletionTimeout_8','0x0528',0,1),(987,'PcieRpCompletionTimeout_9','0x0529',0,1),(988,'PcieRpCompletionTimeout_10','0x052a',0,1),(989,'PcieRpCompletionTimeout_11','0x052b',0,1),(990,'PcieRpCompletionTimeout_12','0x052c',0,1),(991,'PcieRpCompletionTimeout_13','0x052d',0,1),(992,'PcieRpCompletionTimeout_14','0x052e',0,1),(993,'PcieRpCompletionTimeout_15','0x052f',0,1),(994,'PcieRpCompletionTimeout_16','0x0530',0,1),(995,'PcieRpCompletionTimeout_17','0x0531',0,1),(996,'PcieRpCompletionTimeout_18','0x0532',0,1),(997,'PcieRpCompletionTimeout_19','0x0533',0,1),(998,'PcieRpCompletionTimeout_20','0x0534',0,1),(999,'PcieRpCompletionTimeout_21','0x0535',0,1),(1000,'PcieRpCompletionTimeout_22','0x0536',0,1),(1001,'PcieRpCompletionTimeout_23','0x0537',0,1),(1002,'PcieRpDeviceResetPad_0','0x0538',0,4),(1003,'PcieRpDeviceResetPad_1','0x053c',0,4),(1004,'PcieRpDeviceResetPad_2','0x0540',0,4),(1005,'PcieRpDeviceResetPad_3','0x0544',0,4),(1006,'PcieRpDeviceResetPad_4','0x0548',0,4),(1007,'PcieRpDeviceResetPad_5','0x054c',0,4),(1008,'PcieRpDeviceResetPad_6','0x0550',0,4),(1009,'PcieRpDeviceResetPad_7','0x0554',0,4),(1010,'PcieRpDeviceResetPad_8','0x0558',0,4),(1011,'PcieRpDeviceResetPad_9','0x055c',0,4),(1012,'PcieRpDeviceResetPad_10','0x0560',0,4),(1013,'PcieRpDeviceResetPad_11','0x0564',0,4),(1014,'PcieRpDeviceResetPad_12','0x0568',0,4),(1015,'PcieRpDeviceResetPad_13','0x056c',0,4),(1016,'PcieRpDeviceResetPad_14','0x0570',0,4),(1017,'PcieRpDeviceResetPad_15','0x0574',0,4),(1018,'PcieRpDeviceResetPad_16','0x0578',0,4),(1019,'PcieRpDeviceResetPad_17','0x057c',0,4),(1020,'PcieRpDeviceResetPad_18','0x0580',0,4),(1021,'PcieRpDeviceResetPad_19','0x0584',0,4),(1022,'PcieRpDeviceResetPad_20','0x0588',0,4),(1023,'PcieRpDeviceResetPad_21','0x058c',0,4),(1024,'PcieRpDeviceResetPad_22','0x0590',0,4),(1025,'PcieRpDeviceResetPad_23','0x0594',0,4),(1026,'PcieRpAspm_0','0x0598',4,1),(1027,'PcieRpAspm_1','0x0599',4,1),(1028,'PcieRpAspm_2','0x059a',4,1),(1029,'PcieRpAspm_3','0x059b',4,1),(1030,'PcieRpAspm_4','0x059c',4,1),(1031,'PcieRpAspm_5','0x059d',4,1),(1032,'PcieRpAspm_6','0x059e',4,1),(1033,'PcieRpAspm_7','0x059f',4,1),(1034,'PcieRpAspm_8','0x05a0',4,1),(1035,'PcieRpAspm_9','0x05a1',4,1),(1036,'PcieRpAspm_10','0x05a2',4,1),(1037,'PcieRpAspm_11','0x05a3',4,1),(1038,'PcieRpAspm_12','0x05a4',4,1),(1039,'PcieRpAspm_13','0x05a5',4,1),(1040,'PcieRpAspm_14','0x05a6',4,1),(1041,'PcieRpAspm_15','0x05a7',4,1),(1042,'PcieRpAspm_16','0x05a8',4,1),(1043,'PcieRpAspm_17','0x05a9',4,1),(1044,'PcieRpAspm_18','0x05aa',4,1),(1045,'PcieRpAspm_19','0x05ab',4,1),(1046,'PcieRpAspm_20','0x05ac',4,1),(1047,'PcieRpAspm_21','0x05ad',4,1),(1048,'PcieRpAspm_22','0x05ae',4,1),(1049,'PcieRpAspm_23','0x05af',4,1),(1050,'PcieRpL1Substates_0','0x05b0',3,1),(1051,'PcieRpL1Substates_1','0x05b1',3,1),(1052,'PcieRpL1Substates_2','0x05b2',3,1),(1053,'PcieRpL1Substates_3','0x05b3',3,1),(1054,'PcieRpL1Substates_4','0x05b4',3,1),(1055,'PcieRpL1Substates_5','0x05b5',3,1),(1056,'PcieRpL1Substates_6','0x05b6',3,1),(1057,'PcieRpL1Substates_7','0x05b7',3,1),(1058,'PcieRpL1Substates_8','0x05b8',3,1),(1059,'PcieRpL1Substates_9','0x05b9',3,1),(1060,'PcieRpL1Substates_10','0x05ba',3,1),(1061,'PcieRpL1Substates_11','0x05bb',3,1),(1062,'PcieRpL1Substates_12','0x05bc',3,1),(1063,'PcieRpL1Substates_13','0x05bd',3,1),(1064,'PcieRpL1Substates_14','0x05be',3,1),(1065,'PcieRpL1Substates_15','0x05bf',3,1),(1066,'PcieRpL1Substates_16','0x05c0',3,1),(1067,'PcieRpL1Substates_17','0x05c1',3,1),(1068,'PcieRpL1Substates_18','0x05c2',3,1),(1069,'PcieRpL1Substates_19','0x05c3',3,1),(1070,'PcieRpL1Substates_20','0x05c4',3,1),(1071,'PcieRpL1Substates_21','0x05c5',3,1),(1072,'PcieRpL1Substates_22','0x05c6',3,1),(1073,'PcieRpL1Substates_23','0x05c7',3,1),(1074,'PcieRpLtrEnable_0','0x05c8',0,1),(1075,'PcieRpLtrEnable_1','0x05c9',0,1),(1076,'PcieRpLtrEnable_2','0x05ca',0,1),(1077,'PcieRpLtrEnable_3','0x05cb',0,1),(1078,'PcieRpLtrEnable_4','0x05cc',0,1),(1079,'PcieRpLtrEnable_5','0x05cd',0,1),(1080,'PcieRpLtrEnable_6','0x05ce',0,1),(1081,'PcieRpLtrEnable_7','0x05cf',0,1),(1082,'PcieRpLtrEnable_8','0x05d0',0,1),(1083,'PcieRpLtrEnable_9','0x05d1',0,1),(1084,'PcieRpLtrEnable_10','0x05d2',0,1),(1085,'PcieRpLtrEnable_11','0x05d3',0,1),(1086,'PcieRpLtrEnable_12','0x05d4',0,1),(1087,'PcieRpLtrEnable_13','0x05d5',0,1),(1088,'PcieRpLtrEnable_14','0x05d6',0,1),(1089,'PcieRpLtrEnable_15','0x05d7',0,1),(1090,'PcieRpLtrEnable_16','0x05d8',0,1),(1091,'PcieRpLtrEnable_17','0x05d9',0,1),(1092,'PcieRpLtrEnable_18','0x05da',0,1),(1093,'PcieRpLtrEnable_19','0x05db',0,1),(1094,'PcieRpLtrEnable_20','0x05dc',0,1),(1095,'PcieRpLtrEnable_21','0x05dd',0,1),(1096,'PcieRpLtrEnable_22','0x05de',0,1),(1097,'PcieRpLtrEnable_23','0x05df',0,1),(1098,'PcieRpLtrConfigLock_0','0x05e0',0,1),(1099,'PcieRpLtrConfigLock_1','0x05e1',0,1),(1100,'PcieRpLtrConfigLock_2','0x05e2',0,1),(1101,'PcieRpLtrConfigLock_3','0x05e3',0,1),(1102,'PcieRpLtrConfigLock_4','0x05e4',0,1),(1103,'PcieRpLtrConfigLock_5','0x05e5',0,1),(1104,'PcieRpLtrConfigLock_6','0x05e6',0,1),(1105,'PcieRpLtrConfigLock_7','0x05e7',0,1),(1106,'PcieRpLtrConfigLock_8','0x05e8',0,1),(1107,'PcieRpLtrConfigLock_9','0x05e9',0,1),(1108,'PcieRpLtrConfigLock_10','0x05ea',0,1),(1109,'PcieRpLtrConfigLock_11','0x05eb',0,1),(1110,'PcieRpLtrConfigLock_12','0x05ec',0,1),(1111,'PcieRpLtrConfigLock_13','0x05ed',0,1),(1112,'PcieRpLtrConfigLock_14','0x05ee',0,1),(1113,'PcieRpLtrConfigLock_15','0x05ef',0,1),(1114,'PcieRpLtrConfigLock_16','0x05f0',0,1),(1115,'PcieRpLtrConfigLock_17','0x05f1',0,1),(1116,'PcieRpLtrConfigLock_18','0x05f2',0,1),(1117,'PcieRpLtrConfigLock_19','0x05f3',0,1),(1118,'PcieRpLtrConfigLock_20','0x05f4',0,1),(1119,'PcieRpLtrConfigLock_21','0x05f5',0,1),(1120,'PcieRpLtrConfigLock_22','0x05f6',0,1),(1121,'PcieRpLtrConfigLock_23','0x05f7',0,1),(1122,'PcieEqPh3LaneParamCm_0','0x05f8',6,1),(1123,'PcieEqPh3LaneParamCm_1','0x05f9',6,1),(1124,'PcieEqPh3LaneParamCm_2','0x05fa',6,1),(1125,'PcieEqPh3LaneParamCm_3','0x05fb',6,1),(1126,'PcieEqPh3LaneParamCm_4','0x05fc',6,1),(1127,'PcieEqPh3LaneParamCm_5','0x05fd',6,1),(1128,'PcieEqPh3LaneParamCm_6','0x05fe',6,1),(1129,'PcieEqPh3LaneParamCm_7','0x05ff',6,1),(1130,'PcieEqPh3LaneParamCm_8','0x0600',6,1),(1131,'PcieEqPh3LaneParamCm_9','0x0601',6,1),(1132,'PcieEqPh3LaneParamCm_10','0x0602',6,1),(1133,'PcieEqPh3LaneParamCm_11','0x0603',6,1),(1134,'PcieEqPh3LaneParamCm_12','0x0604',6,1),(1135,'PcieEqPh3LaneParamCm_13','0x0605',6,1),(1136,'PcieEqPh3LaneParamCm_14','0x0606',6,1),(1137,'PcieEqPh3LaneParamCm_15','0x0607',6,1),(1138,'PcieEqPh3LaneParamCm_16','0x0608',6,1),(1139,'PcieEqPh3LaneParamCm_17','0x0609',6,1),(1140,'PcieEqPh3LaneParamCm_18','0x060a',6,1),(1141,'PcieEqPh3LaneParamCm_19','0x060b',6,1),(1142,'PcieEqPh3LaneParamCm_20','0x060c',6,1),(1143,'PcieEqPh3LaneParamCm_21','0x060d',6,1),(1144,'PcieEqPh3LaneParamCm_22','0x060e',6,1),(1145,'PcieEqPh3LaneParamCm_23','0x060f',6,1),(1146,'PcieEqPh3LaneParamCp_0','0x0610',2,1),(1147,'PcieEqPh3LaneParamCp_1','0x0611',2,1),(1148,'PcieEqPh3LaneParamCp_2','0x0612',2,1),(1149,'PcieEqPh3LaneParamCp_3','0x0613',2,1),(1150,'PcieEqPh3LaneParamCp_4','0x0614',2,1),(1151,'PcieEqPh3LaneParamCp_5','0x0615',2,1),(1152,'PcieEqPh3LaneParamCp_6','0x0616',2,1),(1153,'PcieEqPh3LaneParamCp_7','0x0617',2,1),(1154,'PcieEqPh3LaneParamCp_8','0x0618',2,1),(1155,'PcieEqPh3LaneParamCp_9','0x0619',2,1),(1156,'PcieEqPh3LaneParamCp_10','0x061a',2,1),(1157,'PcieEqPh3LaneParamCp_11','0x061b',2,1),(1158,'PcieEqPh3LaneParamCp_12','0x061c',2,1),(1159,'PcieEqPh3LaneParamCp_13','0x061d',2,1),(1160,'PcieEqPh3LaneParamCp_14','0x061e',2,1),(1161,'PcieEqPh3LaneParamCp_15','0x061f',2,1),(1162,'PcieEqPh3LaneParamCp_16','0x0620',2,1),(1163,'PcieEqPh3LaneParamCp_17','0x0621',2,1),(1164,'PcieEqPh3LaneParamCp_18','0x0622',2,1),(1165,'PcieEqPh3LaneParamCp_19','0x0623',2,1),(1166,'PcieEqPh3LaneParamCp_20','0x0624',2,1),(1167,'PcieEqPh3LaneParamCp_21','0x0625',2,1),(1168,'PcieEqPh3LaneParamCp_22','0x0626',2,1),(1169,'PcieEqPh3LaneParamCp_23','0x0627',2,1),(1170,'PcieSwEqCoeffListCm_0','0x0628',6,1),(1171,'PcieSwEqCoeffListCm_1','0x0629',4,1),(1172,'PcieSwEqCoeffListCm_2','0x062a',8,1),(1173,'PcieSwEqCoeffListCm_3','0x062b',2,1),(1174,'PcieSwEqCoeffListCm_4','0x062c',10,1),(1175,'PcieSwEqCoeffListCp_0','0x062d',2,1),(1176,'PcieSwEqCoeffListCp_1','0x062e',2,1),(1177,'PcieSwEqCoeffListCp_2','0x062f',2,1),(1178,'PcieSwEqCoeffListCp_3','0x0630',2,1),(1179,'PcieSwEqCoeffListCp_4','0x0631',2,1),(1180,'PcieDisableRootPortClockGating','0x0632',0,1),(1181,'PcieEnablePeerMemoryWrite','0x0633',0,1),(1182,'PcieAllowNoLtrIccPllShutdown','0x0634',0,1),(1183,'PcieComplianceTestMode','0x0635',0,1),(1184,'PcieDetectTimeoutMs','0x0636',0,2),(1185,'PcieRpFunctionSwap','0x0638',1,1),(1186,'PchPmPmeB0S5Dis','0x0639',0,1),(1187,'PchPmSlpS0VmEnable','0x063a',1,1),(1188,'PchPmWolEnableOverride','0x0640',1,1),(1189,'PchPmPcieWakeFromDeepSx','0x0641',0,1),(1190,'PchPmWoWlanEnable','0x0642',0,1),(1191,'PchPmWoWlanDeepSxEnable','0x0643',0,1),(1192,'PchPmLanWakeFromDeepSx','0x0644',1,1),(1193,'PchPmDeepSxPol','0x0645',0,1),(1194,'PchPmSlpS3MinAssert','0x0646',2,1),(1195,'PchPmSlpS4MinAssert','0x0647',4,1),(1196,'PchPmSlpSusMinAssert','0x0648',3,1),(1197,'PchPmSlpAMinAssert','0x0649',3,1),(1198,'PchPmLpcClockRun','0x0650',0,1),(1199,'PchPmSlpStrchSusUp','0x0651',0,1),(1200,'PchPmSlpLanLowDc','0x0652',1,1),(1201,'PchPmPwrBtnOverridePeriod','0x0653',0,1),(1202,'PchPmDisableDsxAcPresentPulldown','0x0654',0,1),(1203,'PchPmCapsuleResetType','0x0655',0,1),(1204,'PchPmDisableNativePowerButton','0x0656',0,1),(1205,'PchPmSlpS0Enable','0x0657',1,1),(1206,'PchPmMeWakeSts','0x0658',1,1),(1207,'PchPmWolOvrWkSts','0x0659',1,1),(1208,'PchPmPwrCycDur','0x065a',0,1),(1209,'PchPort61hEnable','0x065c',0,1),(1210,'SataPwrOptEnable','0x065d',0,1),(1211,'EsataSpeedLimit','0x065e',0,1),(1212,'SataSpeedLimit','0x065f',0,1),(1213,'SataPortsHotPlug_0','0x0660',0,1),(1214,'SataPortsHotPlug_1','0x0661',0,1),(1215,'SataPortsHotPlug_2','0x0662',0,1),(1216,'SataPortsHotPlug_3','0x0663',0,1),(1217,'SataPortsHotPlug_4','0x0664',0,1),(1218,'SataPortsHotPlug_5','0x0665',0,1),(1219,'SataPortsHotPlug_6','0x0666',0,1),(1220,'SataPortsHotPlug_7','0x0667',0,1),(1221,'SataPortsInterlockSw_0','0x0668',0,1),(1222,'SataPortsInterlockSw_1','0x0669',0,1),(1223,'SataPortsInterlockSw_2','0x066a',0,1),(1224,'SataPortsInterlockSw_3','0x066b',0,1),(1225,'SataPortsInterlockSw_4','0x066c',0,1),(1226,'SataPortsInterlockSw_5','0x066d',0,1),(1227,'SataPortsInterlockSw_6','0x066e',0,1),(1228,'SataPortsInterlockSw_7','0x066f',0,1),(1229,'SataPortsExternal_0','0x0670',0,1),(1230,'SataPortsExternal_1','0x0671',0,1),(1231,'SataPortsExternal_2','0x0672',0,1),(1232,'SataPortsExternal_3','0x0673',0,1),(1233,'SataPortsExternal_4','0x0674',0,1),(1234,'SataPortsExternal_5','0x0675',0,1),(1235,'SataPortsExternal_6','0x0676',0,1),(1236,'SataPortsExternal_7','0x0677',0,1),(1237,'SataPortsSpinUp_0','0x0678',0,1),(1238,'SataPortsSpinUp_1','0x0679',0,1),(1239,'SataPortsSpinUp_2','0x067a',0,1),(1240,'SataPortsSpinUp_3','0x067b',0,1),(1241,'SataPortsSpinUp_4','0x067c',0,1),(1242,'SataPortsSpinUp_5','0x067d',0,1),(1243,'SataPortsSpinUp_6','0x067e',0,1),(1244,'SataPortsSpinUp_7','0x067f',0,1),(1245,'SataPortsSolidStateDrive_0','0x0680',0,1),(1246,'SataPortsSolidStateDrive_1','0x0681',0,1),(1247,'SataPortsSolidStateDrive_2','0x0682',0,1),(1248,'SataPortsSolidStateDrive_3','0x0683',0,1),(1249,'SataPortsSolidStateDrive_4','0x0684',0,1),(1250,'SataPortsSolidStateDrive_5','0x0685',0,1),(1251,'SataPortsSolidStateDrive_6','0x0686',0,1),(1252,'SataPortsSolidStateDrive_7','0x0687',0,1),(1253,'SataPortsEnableDitoConfig_0','0x0688',0,1),(1254,'SataPortsEnableDitoConfig_1','0x0689',0,1),(1255,'SataPortsEnableDitoConfig_2','0x068a',0,1),(1256,'SataPortsEnableDitoConfig_3','0x068b',0,1),(1257,'SataPortsEnableDitoConfig_4','0x068c',0,1),(1258,'SataPortsEnableDitoConfig_5','0x068d',0,1),(1259,'SataPortsEnableDitoConfig_6','0x068e',0,1),(1260,'SataPortsEnableDitoConfig_7','0x068f',0,1),(1261,'SataPortsDmVal_0','0x0690',15,1),(1262,'SataPortsDmVal_1','0x0691',15,1),(1263,'SataPortsDmVal_2','0x0692',15,1),(1264,'SataPortsDmVal_3','0x0693',15,1),(1265,'SataPortsDmVal_4','0x0694',15,1),(1266,'SataPortsDmVal_5','0x0695',15,1),(1267,'SataPortsDmVal_6','0x0696',15,1),(1268,'SataPortsDmVal_7','0x0697',15,1),(1269,'SataPortsDitoVal_0','0x0698',28930,2),(1270,'SataPortsDitoVal_1','0x069a',28930,2),(1271,'SataPortsDitoVal_2','0x069c',28930,2),(1272,'SataPortsDitoVal_3','0x069e',28930,2),(1273,'SataPortsDitoVal_4','0x06a0',28930,2),(1274,'SataPortsDitoVal_5','0x06a2',28930,2),(1275,'SataPortsDitoVal_6','0x06a4',28930,2),(1276,'SataPortsDitoVal_7','0x06a6',28930,2),(1277,'SataPortsZpOdd_0','0x06a8',0,1),(1278,'SataPortsZpOdd_1','0x06a9',0,1),(1279,'SataPortsZpOdd_2','0x06aa',0,1),(1280,'SataPortsZpOdd_3','0x06ab',0,1),(1281,'SataPortsZpOdd_4','0x06ac',0,1),(1282,'SataPortsZpOdd_5','0x06ad',0,1),(1283,'SataPortsZpOdd_6','0x06ae',0,1),(1284,'SataPortsZpOdd_7','0x06af',0,1),(1285,'SataRstRaidAlternateId','0x06b0',0,1),(1286,'SataRstRaid0','0x06b1',1,1),(1287,'SataRstRaid1','0x06b2',1,1),(1288,'SataRstRaid10','0x06b3',1,1),(1289,'SataRstRaid5','0x06b4',1,1),(1290,'SataRstIrrt','0x06b5',1,1),(1291,'SataRstOromUiBanner','0x06b6',1,1),(1292,'SataRstOromUiDelay','0x06b7',0,1),(1293,'SataRstHddUnlock','0x06b8',1,1),(1294,'SataRstLedLocate','0x06b9',1,1),(1295,'SataRstIrrtOnly','0x06ba',1,1),(1296,'SataRstSmartStorage','0x06bb',1,1),(1297,'SataRstPcieEnable_0','0x06bc',0,1),(1298,'SataRstPcieEnable_1','0x06bd',0,1),(1299,'SataRstPcieEnable_2','0x06be',0,1),(1300,'SataRstPcieStoragePort_0','0x06bf',0,1),(1301,'SataRstPcieStoragePort_1','0x06c0',0,1),(1302,'SataRstPcieStoragePort_2','0x06c1',0,1),(1303,'SataRstPcieDeviceResetDelay_0','0x06c2',100,1),(1304,'SataRstPcieDeviceResetDelay_1','0x06c3',100,1),(1305,'SataRstPcieDeviceResetDelay_2','0x06c4',100,1),(1306,'PchScsEmmcHs400TuningRequired','0x06c5',0,1),(1307,'PchScsEmmcHs400DllDataValid','0x06c6',0,1),(1308,'PchScsEmmcHs400RxStrobeDll1','0x06c7',0,1),(1309,'PchScsEmmcHs400TxDataDll','0x06c8',0,1),(1310,'PchScsEmmcHs400DriverStrength','0x06c9',0,1),(1311,'SerialIoGpio','0x06ca',1,1),(1312,'SerialIoI2cVoltage_0','0x06cb',0,1),(1313,'SerialIoI2cVoltage_1','0x06cc',0,1),(1314,'SerialIoI2cVoltage_2','0x06cd',0,1),(1315,'SerialIoI2cVoltage_3','0x06ce',0,1),(1316,'SerialIoI2cVoltage_4','0x06cf',0,1),(1317,'SerialIoI2cVoltage_5','0x06d0',0,1),(1318,'SerialIoSpiCsPolarity_0','0x06d1',0,1),(1319,'SerialIoSpiCsPolarity_1','0x06d2',0,1),(1320,'SerialIoUartHwFlowCtrl_0','0x06d3',0,1),(1321,'SerialIoUartHwFlowCtrl_1','0x06d4',0,1),(1322,'SerialIoUartHwFlowCtrl_2','0x06d5',0,1),(1323,'SerialIoDebugUartNumber','0x06d6',2,1),(1324,'SerialIoEnableDebugUartAfterPost','0x06d7',1,1),(1325,'PchSirqEnable','0x06d8',1,1),(1326,'PchSirqMode','0x06d9',0,1),(1327,'PchStartFramePulse','0x06da',0,1),(1328,'PchThermalDeviceEnable','0x06db',1,1),(1329,'PchT0Level','0x06dc',0,2),(1330,'PchT1Level','0x06de',0,2),(1331,'PchT2Level','0x06e0',0,2),(1332,'PchTsmicLock','0x06e2',1,1),(1333,'PchTTEnable','0x06e3',0,1),(1334,'PchTTState13Enable','0x06e4',0,1),(1335,'PchTTLock','0x06e5',0,1),(1336,'TTSuggestedSetting','0x06e6',1,1),(1337,'TTCrossThrottling','0x06e7',1,1),(1338,'PchDmiTsawEn','0x06e8',0,1),(1339,'DmiSuggestedSetting','0x06e9',1,1),(1340,'DmiTS0TW','0x06ea',0,1),(1341,'DmiTS1TW','0x06eb',0,1),(1342,'DmiTS2TW','0x06ec',0,1),(1343,'DmiTS3TW','0x06ed',0,1),(1344,'SataP0T1M','0x06ee',1,1),(1345,'SataP0T2M','0x06ef',2,1),(1346,'SataP0T3M','0x06f0',3,1),(1347,'SataP0TDisp','0x06f1',0,1),(1348,'SataP1T1M','0x06f2',1,1),(1349,'SataP1T2M','0x06f3',2,1),(1350,'SataP1T3M','0x06f4',3,1),(1351,'SataP1TDisp','0x06f5',0,1),(1352,'SataP0Tinact','0x06f6',0,1),(1353,'SataP0TDispFinit','0x06f7',0,1),(1354,'SataP1Tinact','0x06f8',0,1),(1355,'SataP1TDispFinit','0x06f9',0,1),(1356,'SataThermalSuggestedSetting','0x06fa',1,1),(1357,'PchMemoryThrottlingEnable','0x06fb',0,1),(1358,'PchMemoryPmsyncEnable_0','0x06fc',0,1),(1359,'PchMemoryPmsyncEnable_1','0x06fd',0,1),(1360,'PchMemoryC0TransmitEnable_0','0x06fe',0,1),(1361,'PchMemoryC0TransmitEnable_1','0x06ff',0,1),(1362,'PchMemoryPinSelection_0','0x0700',0,1),(1363,'PchMemoryPinSelection_1','0x0701',0,1),(1364,'PchTemperatureHotLevel','0x0702',0,2),(1365,'PchDisableComplianceMode','0x0704',0,1),(1366,'Usb2OverCurrentPin_0','0x0705',0,1),(1367,'Usb2OverCurrentPin_1','0x0706',0,1),(1368,'Usb2OverCurrentPin_2','0x0707',1,1),(1369,'Usb2OverCurrentPin_3','0x0708',1,1),(1370,'Usb2OverCurrentPin_4','0x0709',2,1),(1371,'Usb2OverCurrentPin_5','0x070a',2,1),(1372,'Usb2OverCurrentPin_6','0x070b',3,1),(1373,'Usb2OverCurrentPin_7','0x070c',3,1),(1374,'Usb2OverCurrentPin_8','0x070d',4,1),(1375,'Usb2OverCurrentPin_9','0x070e',4,1),(1376,'Usb2OverCurrentPin_10','0x070f',5,1),(1377,'Usb2OverCurrentPin_11','0x0710',5,1),(1378,'Usb2OverCurrentPin_12','0x0711',6,1),(1379,'Usb2OverCurrentPin_13','0x0712',6,1),(1380,'Usb2OverCurrentPin_14','0x0713',7,1),(1381,'Usb2OverCurrentPin_15','0x0714',7,1),(1382,'Usb3OverCurrentPin_0','0x0715',0,1),(1383,'Usb3OverCurrentPin_1','0x0716',0,1),(1384,'Usb3OverCurrentPin_2','0x0717',1,1),(1385,'Usb3OverCurrentPin_3','0x0718',1,1),(1386,'Usb3OverCurrentPin_4','0x0719',2,1),(1387,'Usb3OverCurrentPin_5','0x071a',2,1),(1388,'Usb3OverCurrentPin_6','0x071b',3,1),(1389,'Usb3OverCurrentPin_7','0x071c',3,1),(1390,'Usb3OverCurrentPin_8','0x071d',4,1),(1391,'Usb3OverCurrentPin_9','0x071e',4,1),(1392,'Early8254ClockGatingEnable','0x071f',0,1),(1393,'SataRstOptaneMemory','0x0720',1,1),(1394,'SataRstCpuAttachedStorage','0x0721',1,1),(1395,'HybridStorageMode','0x0722',0,1),(1396,'PchPcieDeviceOverrideTablePtr','0x0724',0,4),(1397,'EnableTcoTimer','0x0728',0,1),(1398,'EcCmdProvisionEav','0x0729',255,1),(1399,'EcCmdLock','0x072a',255,1),(1400,'SendEcCmd','0x0730',18446744073709551615,8),(1401,'BgpdtHash_0','0x0738',0,8),(1402,'BgpdtHash_1','0x0740',0,8),(1403,'BgpdtHash_2','0x0748',0,8),(1404,'BgpdtHash_3','0x0750',0,8),(1405,'BiosGuardModulePtr','0x0758',18446744073709551615,8),(1406,'BiosGuardAttr','0x0760',4294967295,4),(1407,'SgxSinitNvsData','0x0764',255,1),(1408,'SgxEpoch0','0x0768',18446744073709551615,8),(1409,'SgxEpoch1','0x0770',18446744073709551615,8),(1410,'MeUnconfigOnRtcClear','0x0778',1,1),(1411,'MeUnconfigIsValid','0x0779',1,1),(1412,'IslVrCmd','0x077a',0,1);
-/*!40000 ALTER TABLE `updDefaults_kabylake` ENABLE KEYS */;
-UNLOCK TABLES;
-/*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
-
-/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
-/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
-/*!40014 SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS */;
-/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
-/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
-/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
-/*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
-
--- Dump completed on 2020-01-07 14:37:13
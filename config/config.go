@@ -6,35 +6,103 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+// FakeRule - Replace a value read by the DUT at the given IP and address (BL_FAKE)
+type FakeRule struct {
+	IP        uint   `yaml:"ip"`
+	Address   uint   `yaml:"address"`
+	Value     uint64 `yaml:"value"`
+	Type      string `yaml:"type"`
+	Direction string `yaml:"direction"`
+}
+
+// Filter - Accesses the DUT must not report (BL_FILTER)
+type Filter struct {
+	Type    string `yaml:"type"`
+	Address uint   `yaml:"address"`
+}
+
+// Signal - Matches a single trace entry
+// Type and Direction "*" or "" and DataWidth 0 match everything.
+// OffsetMask and ValueMask select the bits to compare, 0 compares all bits.
+// If ValueMax is non zero the masked value must be within ValueMin and ValueMax
+// instead of being equal to Value.
+type Signal struct {
+	Type       string `yaml:"type"`
+	Offset     uint   `yaml:"offset"`
+	OffsetMask uint   `yaml:"offsetmask"`
+	Value      uint64 `yaml:"value"`
+	ValueMask  uint64 `yaml:"valuemask"`
+	ValueMin   uint64 `yaml:"valuemin"`
+	ValueMax   uint64 `yaml:"valuemax"`
+	Direction  string `yaml:"direction"`
+	DataWidth  uint   `yaml:"datawidth"`
+}
+
+// Window - A named part of the trace between a start and a stop signal
+type Window struct {
+	Name  string `yaml:"name"`
+	Start Signal `yaml:"start"`
+	Stop  Signal `yaml:"stop"`
+}
+
+// Serial - Connection to the autorev shell of the DUT
+type Serial struct {
+	Type                 string `yaml:"type"`
+	Port                 string `yaml:"port"`
+	BaudRate             int    `yaml:"baudrate"`
+	ReadWriteTimeout     uint   `yaml:"timeout"`
+	DeviceHotplugTimeout uint   `yaml:"hotplugtimeout"`
+	Listen               bool   `yaml:"listen"`
+	DataBits             uint   `yaml:"databits"`
+	Parity               string `yaml:"parity"`
+	StopBits             string `yaml:"stopbits"`
+}
+
+// DutControl - How to power and reset the DUT
+type DutControl struct {
+	Type       string `yaml:"type"` // shell (default), qmp or ipmi
+	StartCmd   string `yaml:"startcmd"`
+	StopCmd    string `yaml:"stopcmd"`
+	RestartCmd string `yaml:"restartcmd"`
+	InitCmd    string `yaml:"initcmd"`
+	Timeout    uint   `yaml:"timeout"` // Seconds each command may run, defaults to 60
+	Qmp        struct {
+		Binary  string   `yaml:"binary"`  // QEMU to launch, empty to use a running one
+		Args    []string `yaml:"args"`    // QEMU arguments, -qmp and -S are added
+		Socket  string   `yaml:"socket"`  // QMP unix socket
+		Chardev string   `yaml:"chardev"` // Chardev of the serial, defaults to serial0
+	} `yaml:"qmp"`
+	Ipmi struct {
+		Host     string `yaml:"host"`     // BMC host[:port]
+		Username string `yaml:"username"` // Defaults to $AUTOREV_IPMI_USERNAME
+		Password string `yaml:"password"` // Defaults to $AUTOREV_IPMI_PASSWORD
+	} `yaml:"ipmi"`
+}
+
+// Dut - A DUT profile for parallel trace collection
+// Serial and DutControl replace the global sections, use YAML anchors to share
+// common settings between profiles.
+type Dut struct {
+	Name       string     `yaml:"name"`
+	Serial     Serial     `yaml:"serial"`
+	DutControl DutControl `yaml:"dutcontrol"`
+}
+
 type Config struct {
 	TraceLog struct {
-		StartSignal struct {
-			Type      string `yaml:"type"`
-			Offset    uint   `yaml:"offset"`
-			Value     uint64 `yaml:"value"`
-			Direction string `yaml:"direction"`
-			DataWidth uint   `yaml:"datawidth"`
-		} `yaml:"startsignal"`
-		StopSignal struct {
-			Type      string `yaml:"type"`
-			Offset    uint   `yaml:"offset"`
-			Value     uint64 `yaml:"value"`
-			Direction string `yaml:"direction"`
-			DataWidth uint   `yaml:"datawidth"`
-		} `yaml:"stopsignal"`
-		Serial struct {
-			Type                 string `yaml:"type"`
-			Port                 string `yaml:"port"`
-			BaudRate             int    `yaml:"baudrate"`
-			ReadWriteTimeout     uint   `yaml:"timeout"`
-			DeviceHotplugTimeout uint   `yaml:"hotplugtimeout"`
-		} `yaml:"serial"`
-		DutControl struct {
-			StartCmd   string `yaml:"startcmd"`
-			StopCmd    string `yaml:"stopcmd"`
-			RestartCmd string `yaml:"restartcmd"`
-			InitCmd    string `yaml:"initcmd"`
-		} `yaml:"dutcontrol"`
+		StartSignal Signal   `yaml:"startsignal"`
+		StopSignal  Signal   `yaml:"stopsignal"`
+		Windows     []Window `yaml:"windows"`
+
+		Serial     Serial     `yaml:"serial"`
+		DutControl DutControl `yaml:"dutcontrol"`
+		// DUTs to collect traces on in parallel, empty to use serial and dutcontrol
+		Duts     []Dut `yaml:"duts"`
+		Watchdog struct {
+			Timeout    uint `yaml:"timeout"`    // Wall-clock seconds per attempt, 0 disables
+			Inactivity uint `yaml:"inactivity"` // Seconds without data while tracing
+			Retries    uint `yaml:"retries"`    // Additional attempts after a failure
+		} `yaml:"watchdog"`
 		VariableFirmareOptions []struct {
 			Name       string `yaml:"name"`
 			ByteOffset uint   `yaml:"byteoffset"`
@@ -42,13 +110,33 @@ type Config struct {
 			Min        uint64 `yaml:"min"`
 			Max        uint64 `yaml:"max"`
 		} `yaml:"variable_options"`
-		OptionsDefaultTable string `yaml:"options_default_table"`
+		OptionsDefaultTable string     `yaml:"options_default_table"`
+		FakeRules           []FakeRule `yaml:"fake"`
+		Filters             []Filter   `yaml:"filter"`
+		BinaryTrace         bool       `yaml:"binarytrace"`
+		// CPUID feature bits used as firmware options, e.g. cpuid_7_0_ebx_5
+		CPUIDFeatures []string `yaml:"cpuid_features"`
+		// Runs of every config, more than one detects non-deterministic accesses
+		Repeat uint `yaml:"repeat"`
+		// How tests are generated from the variable options
+		Generation struct {
+			Strategy string `yaml:"strategy"` // full (default), pairwise, twise, random or adaptive
+			Strength int    `yaml:"strength"` // Options interacting for twise and adaptive, defaults to 3
+			Samples  int    `yaml:"samples"`  // Tests drawn by random
+			Seed     int64  `yaml:"seed"`     // The same seed generates the same tests
+		} `yaml:"generation"`
 	}
 	Database struct {
-		HostName string `yaml:"hostname"` // Ignoring for now
-		Port     uint   `yaml:"port"`     // Ignoring for now
+		// mysql (default) or file
+		Driver   string `yaml:"driver"`
+		HostName string `yaml:"hostname"` // Empty to use the local socket
+		Port     uint   `yaml:"port"`
 		Username string `yaml:"username"`
 		Password string `yaml:"password"`
+		// Database file of the file driver
+		Path string `yaml:"path"`
+		// Trace entries written per transaction while capturing, 0 for default
+		BatchSize int `yaml:"batchsize"`
 	} `yaml:"database"`
 }
 
@@ -66,6 +154,58 @@ func GetConfig() (Config, error) {
 	return cfg, err
 }
 
+// GetFakeRules - Read a list of fake rules from a YAML file
+func GetFakeRules(filename string) ([]FakeRule, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []FakeRule
+	decoder := yaml.NewDecoder(f)
+
+	err = decoder.Decode(&rules)
+	return rules, err
+}
+
+// ForDut - Returns the config with serial and dutcontrol of the DUT profile
+func (c Config) ForDut(d Dut) Config {
+	c.TraceLog.Serial = d.Serial
+	c.TraceLog.DutControl = d.DutControl
+	return c
+}
+
+// CampaignSnapshot - Serializes the trace log settings a campaign is defined by,
+// like option definitions, signals, windows, fakes and filters. Serial,
+// dutcontrol, DUT profiles and watchdog are left out as they may change while a
+// campaign runs.
+func (c Config) CampaignSnapshot() ([]byte, error) {
+	var empty Config
+	tl := c.TraceLog
+	tl.Serial = empty.TraceLog.Serial
+	tl.DutControl = empty.TraceLog.DutControl
+	tl.Duts = nil
+	tl.Watchdog = empty.TraceLog.Watchdog
+	return yaml.Marshal(&tl)
+}
+
+// WithCampaign - Returns the config with the trace log settings of a campaign
+// snapshot, the settings left out of the snapshot are kept
+func (c Config) WithCampaign(snapshot []byte) (Config, error) {
+	var s Config
+	err := yaml.Unmarshal(snapshot, &s.TraceLog)
+	if err != nil {
+		return c, err
+	}
+	s.TraceLog.Serial = c.TraceLog.Serial
+	s.TraceLog.DutControl = c.TraceLog.DutControl
+	s.TraceLog.Duts = c.TraceLog.Duts
+	s.TraceLog.Watchdog = c.TraceLog.Watchdog
+	s.Database = c.Database
+	return s, nil
+}
+
 //return all FirmwareOptions and their possible values as map
 func GetConfigFirmwareOptionsByName(cfg Config) map[string][]uint64 {
 	optionsset := map[string][]uint64{}
@@ -75,6 +215,9 @@ func GetConfigFirmwareOptionsByName(cfg Config) map[string][]uint64 {
 			optionsset[opt.Name] = append(optionsset[opt.Name], j)
 		}
 	}
+	for _, name := range cfg.TraceLog.CPUIDFeatures {
+		optionsset[name] = []uint64{0, 1}
+	}
 
 	return optionsset
 }
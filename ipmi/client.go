@@ -0,0 +1,345 @@
+package ipmi
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// IPMI network functions and commands used
+const (
+	netFnChassis = 0x00
+	netFnApp     = 0x06
+
+	cmdGetChassisStatus    = 0x01
+	cmdChassisControl      = 0x02
+	cmdSetSessionPrivilege = 0x3b
+	cmdCloseSession        = 0x3c
+
+	completionCodeNormal = 0x00
+
+	addrBMC           = 0x20
+	addrRemoteConsole = 0x81
+)
+
+// Session setup
+const (
+	privilegeAdministrator  = 0x04
+	privilegeNameOnlyLookup = 0x10
+
+	rakpStatusNoError        = 0x00
+	rakpStatusNoSessionSlots = 0x01
+	rakpStatusInvalidRole    = 0x09
+	rakpStatusUnauthorized   = 0x0d
+	rakpStatusInvalidICV     = 0x0f
+
+	defaultPort    = "623"
+	defaultRetries = 3
+)
+
+// PowerAction - Argument of the chassis control command
+type PowerAction byte
+
+const (
+	// PowerOff - Power down the chassis
+	PowerOff PowerAction = 0x00
+	// PowerOn - Power up the chassis
+	PowerOn PowerAction = 0x01
+	// PowerCycle - Power down the chassis and up again after a BMC defined delay
+	PowerCycle PowerAction = 0x02
+	// HardReset - Pulse the reset line
+	HardReset PowerAction = 0x03
+)
+
+func (a PowerAction) String() string {
+	switch a {
+	case PowerOff:
+		return "off"
+	case PowerOn:
+		return "on"
+	case PowerCycle:
+		return "cycle"
+	case HardReset:
+		return "reset"
+	}
+	return fmt.Sprintf("action %d", a)
+}
+
+// Client - An RMCP+ session with a BMC
+type Client struct {
+	conn     net.Conn
+	timeout  time.Duration
+	retries  int
+	username string
+	password string
+
+	// session ID chosen by us and by the BMC
+	consoleID uint32
+	bmcID     uint32
+	// outbound session sequence number
+	seq uint32
+	// IPMI message sequence number
+	rqSeq byte
+	keys  *sessionKeys
+}
+
+// rakpError - Text for RAKP status codes
+func rakpError(status byte) error {
+	switch status {
+	case rakpStatusUnauthorized:
+		return fmt.Errorf("BMC rejected the username")
+	case rakpStatusInvalidICV:
+		return fmt.Errorf("BMC rejected the password")
+	case rakpStatusInvalidRole:
+		return fmt.Errorf("BMC rejected the administrator privilege level")
+	case rakpStatusNoSessionSlots:
+		return fmt.Errorf("BMC has no free session slot")
+	}
+	return fmt.Errorf("BMC returned RAKP status %02x", status)
+}
+
+// Dial - Open an authenticated and encrypted RMCP+ session with administrator privileges
+// The port defaults to 623. Every request is retried on timeout.
+func Dial(addr string, username string, password string, timeout time.Duration) (*Client, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, defaultPort)
+	}
+	if len(password) > 20 {
+		return nil, fmt.Errorf("IPMI passwords can't be longer than 20 chars")
+	}
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		conn:     conn,
+		timeout:  timeout,
+		retries:  defaultRetries,
+		username: username,
+		password: password,
+	}
+	err = c.openSession()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Failed to open IPMI session with %s: %v", addr, err)
+	}
+	return c, nil
+}
+
+// Close - Close the session and the connection
+func (c *Client) Close() error {
+	if c.keys != nil {
+		c.command(netFnApp, cmdCloseSession, le32(c.bmcID))
+		c.keys = nil
+	}
+	return c.conn.Close()
+}
+
+// kuid - The user key, the password padded to 20 bytes
+func (c *Client) kuid() []byte {
+	k := make([]byte, 20)
+	copy(k, c.password)
+	return k
+}
+
+// lookupKeys - Keys for decoding packets of our session
+func (c *Client) lookupKeys(id uint32) *sessionKeys {
+	if id == c.consoleID {
+		return c.keys
+	}
+	return nil
+}
+
+// roundTrip - Send requests built by build until a matching reply arrives
+func (c *Client) roundTrip(build func() ([]byte, error), match func(*packet) bool) (*packet, error) {
+	buf := make([]byte, 1024)
+	for i := 0; i <= c.retries; i++ {
+		req, err := build()
+		if err != nil {
+			return nil, err
+		}
+		_, err = c.conn.Write(req)
+		if err != nil {
+			return nil, err
+		}
+
+		deadline := time.Now().Add(c.timeout)
+		c.conn.SetReadDeadline(deadline)
+		for {
+			n, err := c.conn.Read(buf)
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			p, err := decodePacket(buf[:n], c.lookupKeys)
+			if err != nil {
+				// Not for us or damaged
+				continue
+			}
+			if match(p) {
+				return p, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Timeout waiting for BMC")
+}
+
+// sessionSetup - Send an unauthenticated session setup payload
+// The reply must echo the tag and our session ID at offset 4.
+func (c *Client) sessionSetup(payloadType byte, payload []byte, replyType byte) ([]byte, error) {
+	req, err := encodePacket(&packet{payloadType: payloadType, payload: payload}, nil)
+	if err != nil {
+		return nil, err
+	}
+	p, err := c.roundTrip(func() ([]byte, error) { return req, nil }, func(p *packet) bool {
+		return p.payloadType == replyType && len(p.payload) >= 2 && p.payload[0] == payload[0]
+	})
+	if err != nil {
+		return nil, err
+	}
+	if p.payload[1] != rakpStatusNoError {
+		return nil, rakpError(p.payload[1])
+	}
+	if len(p.payload) < 8 || binary.LittleEndian.Uint32(p.payload[4:8]) != c.consoleID {
+		return nil, fmt.Errorf("BMC replied with wrong session ID")
+	}
+	return p.payload, nil
+}
+
+// openSession - Open session request and RAKP messages 1-4
+func (c *Client) openSession() error {
+	var rnd [36]byte
+	_, err := rand.Read(rnd[:])
+	if err != nil {
+		return err
+	}
+	c.consoleID = binary.LittleEndian.Uint32(rnd[0:4]) | 1
+	rm := rnd[4:20]
+	tag := rnd[20]
+
+	// Open Session Request
+	req := []byte{tag, privilegeAdministrator, 0, 0}
+	req = append(req, le32(c.consoleID)...)
+	req = append(req, 0x00, 0, 0, 8, algoAuthRAKPHMACSHA1, 0, 0, 0)
+	req = append(req, 0x01, 0, 0, 8, algoIntegrityHMACSHA196, 0, 0, 0)
+	req = append(req, 0x02, 0, 0, 8, algoConfAESCBC128, 0, 0, 0)
+	resp, err := c.sessionSetup(payloadOpenSessionRequest, req, payloadOpenSessionResponse)
+	if err != nil {
+		return err
+	}
+	if len(resp) < 36 {
+		return fmt.Errorf("Open session response too short")
+	}
+	c.bmcID = binary.LittleEndian.Uint32(resp[8:12])
+	if resp[16] != algoAuthRAKPHMACSHA1 || resp[24] != algoIntegrityHMACSHA196 || resp[32] != algoConfAESCBC128 {
+		return fmt.Errorf("BMC doesn't support cipher suite 3")
+	}
+
+	// RAKP 1 and 2
+	role := byte(privilegeAdministrator | privilegeNameOnlyLookup)
+	user := []byte(c.username)
+	tag++
+	req = []byte{tag, 0, 0, 0}
+	req = append(req, le32(c.bmcID)...)
+	req = append(req, rm...)
+	req = append(req, role, 0, 0, byte(len(user)))
+	req = append(req, user...)
+	resp, err = c.sessionSetup(payloadRAKP1, req, payloadRAKP2)
+	if err != nil {
+		return err
+	}
+	if len(resp) < 60 {
+		return fmt.Errorf("RAKP 2 too short")
+	}
+	rc := resp[8:24]
+	guid := resp[24:40]
+	kuid := c.kuid()
+	want := hmacSHA1(kuid, le32(c.consoleID), le32(c.bmcID), rm, rc, guid, []byte{role, byte(len(user))}, user)
+	if !hmac.Equal(resp[40:60], want) {
+		return fmt.Errorf("Wrong password")
+	}
+
+	// RAKP 3 and 4
+	tag++
+	req = []byte{tag, rakpStatusNoError, 0, 0}
+	req = append(req, le32(c.bmcID)...)
+	req = append(req, hmacSHA1(kuid, rc, le32(c.consoleID), []byte{role, byte(len(user))}, user)...)
+	resp, err = c.sessionSetup(payloadRAKP3, req, payloadRAKP4)
+	if err != nil {
+		return err
+	}
+	if len(resp) < 8+authCodeLength {
+		return fmt.Errorf("RAKP 4 too short")
+	}
+	sik := hmacSHA1(kuid, rm, rc, []byte{role, byte(len(user))}, user)
+	if !hmac.Equal(resp[8:8+authCodeLength], hmacSHA1(sik, rm, le32(c.bmcID), guid)[:authCodeLength]) {
+		return fmt.Errorf("BMC sent wrong integrity check value")
+	}
+	c.keys = deriveKeys(sik)
+
+	// Sessions start with user privileges
+	_, err = c.command(netFnApp, cmdSetSessionPrivilege, []byte{privilegeAdministrator})
+	return err
+}
+
+// command - Send an IPMI command in the session and return the response data
+func (c *Client) command(netFn byte, cmd byte, data []byte) ([]byte, error) {
+	if c.keys == nil {
+		return nil, fmt.Errorf("No active session")
+	}
+	c.rqSeq = (c.rqSeq + 1) & 0x3f
+	rqSeq := c.rqSeq
+	msg := encodeMessage(addrBMC, netFn, addrRemoteConsole, rqSeq, cmd, data)
+
+	var resp *message
+	_, err := c.roundTrip(func() ([]byte, error) {
+		c.seq++
+		return encodePacket(&packet{payloadType: payloadIPMI, sessionID: c.bmcID, seq: c.seq, payload: msg}, c.keys)
+	}, func(p *packet) bool {
+		if p.payloadType != payloadIPMI {
+			return false
+		}
+		m, err := decodeMessage(p.payload)
+		if err != nil || m.netFn != netFn|1 || m.rqSeq != rqSeq || m.cmd != cmd {
+			return false
+		}
+		resp = m
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.data) < 1 {
+		return nil, fmt.Errorf("IPMI response without completion code")
+	}
+	if resp.data[0] != completionCodeNormal {
+		return nil, fmt.Errorf("IPMI command %02x:%02x failed with completion code %02x", netFn, cmd, resp.data[0])
+	}
+	return resp.data[1:], nil
+}
+
+// PowerStatus - Returns true if the chassis is powered on
+func (c *Client) PowerStatus() (bool, error) {
+	data, err := c.command(netFnChassis, cmdGetChassisStatus, nil)
+	if err != nil {
+		return false, err
+	}
+	if len(data) < 3 {
+		return false, fmt.Errorf("Chassis status too short")
+	}
+	return data[0]&1 != 0, nil
+}
+
+// ChassisControl - Power the chassis on, off, cycle or reset it
+func (c *Client) ChassisControl(a PowerAction) error {
+	_, err := c.command(netFnChassis, cmdChassisControl, []byte{byte(a)})
+	return err
+}
@@ -0,0 +1,322 @@
+package ipmi
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+)
+
+// FakeBMC - A minimal RMCP+ BMC with a simulated chassis
+// It supports cipher suite 3 and the commands used by Client, which allows to test
+// DUT control without hardware.
+type FakeBMC struct {
+	conn     *net.UDPConn
+	username string
+	password string
+
+	mutex sync.Mutex
+	// time a power change takes to become visible in the chassis status
+	powerDelay time.Duration
+	sessions   map[uint32]*fakeSession
+	// power state and the state it changes to at changeAt
+	power    bool
+	target   bool
+	changeAt time.Time
+	actions  []PowerAction
+}
+
+// fakeSession - State of a session on the BMC side
+type fakeSession struct {
+	consoleID uint32
+	bmcID     uint32
+	rm, rc    []byte
+	guid      []byte
+	role      byte
+	user      []byte
+	keys      *sessionKeys
+	privilege byte
+	seq       uint32
+}
+
+// NewFakeBMC - Listen on addr, e.g. "127.0.0.1:0", for RMCP+ sessions
+func NewFakeBMC(addr string, username string, password string) (*FakeBMC, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return nil, err
+	}
+	b := &FakeBMC{
+		conn:     conn,
+		username: username,
+		password: password,
+		sessions: map[uint32]*fakeSession{},
+	}
+	go b.serve()
+	return b, nil
+}
+
+// Addr - Returns the address the BMC listens on
+func (b *FakeBMC) Addr() string {
+	return b.conn.LocalAddr().String()
+}
+
+// Close - Stop the BMC
+func (b *FakeBMC) Close() error {
+	return b.conn.Close()
+}
+
+// Power - Returns the current chassis power state
+func (b *FakeBMC) Power() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.currentPower()
+}
+
+// SetPower - Set the chassis power state
+func (b *FakeBMC) SetPower(on bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.power, b.target = on, on
+}
+
+// SetPowerDelay - Set the time a power change takes to become visible in the chassis status
+func (b *FakeBMC) SetPowerDelay(d time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.powerDelay = d
+}
+
+// Actions - Returns the chassis control actions received
+func (b *FakeBMC) Actions() []PowerAction {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]PowerAction{}, b.actions...)
+}
+
+// Sessions - Returns the number of open sessions
+func (b *FakeBMC) Sessions() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.sessions)
+}
+
+func (b *FakeBMC) currentPower() bool {
+	if b.power != b.target && !time.Now().Before(b.changeAt) {
+		b.power = b.target
+	}
+	return b.power
+}
+
+func (b *FakeBMC) lookupKeys(id uint32) *sessionKeys {
+	if s, ok := b.sessions[id]; ok {
+		return s.keys
+	}
+	return nil
+}
+
+func (b *FakeBMC) serve() {
+	buf := make([]byte, 1024)
+	for {
+		n, addr, err := b.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		b.mutex.Lock()
+		reply := b.handle(buf[:n])
+		b.mutex.Unlock()
+		if reply != nil {
+			b.conn.WriteToUDP(reply, addr)
+		}
+	}
+}
+
+// handle - Returns the reply to a request, nil to drop it
+func (b *FakeBMC) handle(req []byte) []byte {
+	p, err := decodePacket(req, b.lookupKeys)
+	if err != nil {
+		return nil
+	}
+	var reply []byte
+
+	switch p.payloadType {
+	case payloadOpenSessionRequest:
+		reply = b.openSession(p.payload)
+	case payloadRAKP1:
+		reply = b.rakp1(p.payload)
+	case payloadRAKP3:
+		reply = b.rakp3(p.payload)
+	case payloadIPMI:
+		s := b.sessions[p.sessionID]
+		if s == nil || s.keys == nil {
+			return nil
+		}
+		reply = b.message(s, p.payload)
+		if reply == nil {
+			return nil
+		}
+		s.seq++
+		out, _ := encodePacket(&packet{payloadType: payloadIPMI, sessionID: s.consoleID, seq: s.seq, payload: reply}, s.keys)
+		return out
+	default:
+		return nil
+	}
+	if reply == nil {
+		return nil
+	}
+	out, _ := encodePacket(&packet{payloadType: p.payloadType + 1, payload: reply}, nil)
+	return out
+}
+
+// status - A session setup reply carrying only an error status
+func status(tag byte, code byte, consoleID uint32) []byte {
+	return append([]byte{tag, code, 0, 0}, le32(consoleID)...)
+}
+
+func (b *FakeBMC) openSession(req []byte) []byte {
+	if len(req) < 32 {
+		return nil
+	}
+	s := &fakeSession{consoleID: binary.LittleEndian.Uint32(req[4:8])}
+	var id [4]byte
+	rand.Read(id[:])
+	s.bmcID = binary.LittleEndian.Uint32(id[:]) | 1
+	b.sessions[s.bmcID] = s
+
+	reply := []byte{req[0], rakpStatusNoError, privilegeAdministrator, 0}
+	reply = append(reply, le32(s.consoleID)...)
+	reply = append(reply, le32(s.bmcID)...)
+	reply = append(reply, 0x00, 0, 0, 8, algoAuthRAKPHMACSHA1, 0, 0, 0)
+	reply = append(reply, 0x01, 0, 0, 8, algoIntegrityHMACSHA196, 0, 0, 0)
+	reply = append(reply, 0x02, 0, 0, 8, algoConfAESCBC128, 0, 0, 0)
+	return reply
+}
+
+func (b *FakeBMC) kuid() []byte {
+	k := make([]byte, 20)
+	copy(k, b.password)
+	return k
+}
+
+func (b *FakeBMC) rakp1(req []byte) []byte {
+	if len(req) < 28 || len(req) < 28+int(req[27]) {
+		return nil
+	}
+	s := b.sessions[binary.LittleEndian.Uint32(req[4:8])]
+	if s == nil {
+		return nil
+	}
+	s.rm = append([]byte{}, req[8:24]...)
+	s.role = req[24]
+	s.user = append([]byte{}, req[28:28+int(req[27])]...)
+	if string(s.user) != b.username {
+		delete(b.sessions, s.bmcID)
+		return status(req[0], rakpStatusUnauthorized, s.consoleID)
+	}
+
+	rnd := make([]byte, 32)
+	rand.Read(rnd)
+	s.rc, s.guid = rnd[:16], rnd[16:]
+
+	reply := []byte{req[0], rakpStatusNoError, 0, 0}
+	reply = append(reply, le32(s.consoleID)...)
+	reply = append(reply, s.rc...)
+	reply = append(reply, s.guid...)
+	reply = append(reply, hmacSHA1(b.kuid(), le32(s.consoleID), le32(s.bmcID), s.rm, s.rc, s.guid,
+		[]byte{s.role, byte(len(s.user))}, s.user)...)
+	return reply
+}
+
+func (b *FakeBMC) rakp3(req []byte) []byte {
+	if len(req) < 28 {
+		return nil
+	}
+	s := b.sessions[binary.LittleEndian.Uint32(req[4:8])]
+	if s == nil || s.rc == nil {
+		return nil
+	}
+	kuid := b.kuid()
+	want := hmacSHA1(kuid, s.rc, le32(s.consoleID), []byte{s.role, byte(len(s.user))}, s.user)
+	if !hmac.Equal(req[8:28], want) {
+		delete(b.sessions, s.bmcID)
+		return status(req[0], rakpStatusInvalidICV, s.consoleID)
+	}
+
+	sik := hmacSHA1(kuid, s.rm, s.rc, []byte{s.role, byte(len(s.user))}, s.user)
+	s.keys = deriveKeys(sik)
+	// Sessions start with user privileges
+	s.privilege = 0x02
+
+	reply := []byte{req[0], rakpStatusNoError, 0, 0}
+	reply = append(reply, le32(s.consoleID)...)
+	reply = append(reply, hmacSHA1(sik, s.rm, le32(s.bmcID), s.guid)[:authCodeLength]...)
+	return reply
+}
+
+// message - Handle an IPMI request, returns the response message
+func (b *FakeBMC) message(s *fakeSession, payload []byte) []byte {
+	m, err := decodeMessage(payload)
+	if err != nil {
+		return nil
+	}
+	// rsAddr and rqAddr swap places in the response
+	respond := func(data ...byte) []byte {
+		return encodeMessage(addrRemoteConsole, m.netFn|1, addrBMC, m.rqSeq, m.cmd, data)
+	}
+	const (
+		ccInvalidCommand        = 0xc1
+		ccInvalidData           = 0xcc
+		ccInsufficientPrivilege = 0xd4
+	)
+
+	switch {
+	case m.netFn == netFnApp && m.cmd == cmdSetSessionPrivilege:
+		if len(m.data) < 1 || m.data[0] > s.role&0x0f {
+			return respond(ccInvalidData)
+		}
+		s.privilege = m.data[0]
+		return respond(completionCodeNormal, s.privilege)
+	case m.netFn == netFnApp && m.cmd == cmdCloseSession:
+		delete(b.sessions, s.bmcID)
+		return respond(completionCodeNormal)
+	case m.netFn == netFnChassis && m.cmd == cmdGetChassisStatus:
+		var state byte
+		if b.currentPower() {
+			state = 1
+		}
+		return respond(completionCodeNormal, state, 0, 0)
+	case m.netFn == netFnChassis && m.cmd == cmdChassisControl:
+		// Operator privilege required
+		if s.privilege < 0x03 {
+			return respond(ccInsufficientPrivilege)
+		}
+		if len(m.data) < 1 {
+			return respond(ccInvalidData)
+		}
+		a := PowerAction(m.data[0])
+		b.actions = append(b.actions, a)
+		on := b.currentPower()
+		switch a {
+		case PowerOff:
+			b.target = false
+		case PowerOn:
+			b.target = true
+		case PowerCycle:
+			if on {
+				b.power = false
+				b.target = true
+			}
+		case HardReset:
+		default:
+			return respond(ccInvalidData)
+		}
+		b.changeAt = time.Now().Add(b.powerDelay)
+		return respond(completionCodeNormal)
+	}
+	return respond(ccInvalidCommand)
+}
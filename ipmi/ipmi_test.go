@@ -0,0 +1,117 @@
+package ipmi
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestBMC(t *testing.T) *FakeBMC {
+	b, err := NewFakeBMC("127.0.0.1:0", "admin", "ADMIN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 16)
+	for n := 0; n < 40; n++ {
+		data := bytes.Repeat([]byte{byte(n)}, n)
+		enc, err := encrypt(key, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(enc)%16 != 0 {
+			t.Errorf("Encrypted length %d isn't a multiple of 16", len(enc))
+		}
+		dec, err := decrypt(key, enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(dec, data) {
+			t.Errorf("Got %x, want %x", dec, data)
+		}
+	}
+}
+
+func TestPacketIntegrity(t *testing.T) {
+	keys := deriveKeys(bytes.Repeat([]byte{1}, 20))
+	p := &packet{payloadType: payloadIPMI, sessionID: 0x1234, seq: 5, payload: []byte{1, 2, 3}}
+	b, err := encodePacket(p, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(uint32) *sessionKeys { return keys }
+
+	got, err := decodePacket(b, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("Got %+v, want %+v", got, p)
+	}
+
+	b[len(b)-1] ^= 1
+	if _, err := decodePacket(b, lookup); err == nil {
+		t.Errorf("Expected error for corrupted auth code")
+	}
+}
+
+func TestClientPower(t *testing.T) {
+	b := newTestBMC(t)
+	delay := 50 * time.Millisecond
+	b.SetPowerDelay(delay)
+
+	c, err := Dial(b.Addr(), "admin", "ADMIN", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	on, err := c.PowerStatus()
+	if err != nil || on {
+		t.Fatalf("Got power %v %v, want off", on, err)
+	}
+	if err := c.ChassisControl(PowerOn); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * delay)
+	on, err = c.PowerStatus()
+	if err != nil || !on {
+		t.Errorf("Got power %v %v, want on", on, err)
+	}
+	if err := c.ChassisControl(PowerCycle); err != nil {
+		t.Fatal(err)
+	}
+	on, _ = c.PowerStatus()
+	if on {
+		t.Errorf("Chassis still on while cycling")
+	}
+
+	if b.Sessions() != 1 {
+		t.Errorf("BMC has %d sessions, want 1", b.Sessions())
+	}
+	c.Close()
+	if b.Sessions() != 0 {
+		t.Errorf("Session not closed")
+	}
+	if want := []PowerAction{PowerOn, PowerCycle}; !reflect.DeepEqual(b.Actions(), want) {
+		t.Errorf("BMC got %v, want %v", b.Actions(), want)
+	}
+}
+
+func TestClientWrongCredentials(t *testing.T) {
+	b := newTestBMC(t)
+
+	_, err := Dial(b.Addr(), "root", "ADMIN", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "username") {
+		t.Errorf("Got %v, want username error", err)
+	}
+	_, err = Dial(b.Addr(), "admin", "secret", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "password") {
+		t.Errorf("Got %v, want password error", err)
+	}
+}
@@ -0,0 +1,245 @@
+package ipmi
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+)
+
+// RMCP and IPMI v2.0 session header, see IPMI v2.0 spec chapter 13
+const (
+	rmcpVersion      = 0x06
+	rmcpSeqNoAck     = 0xff
+	rmcpClassIPMI    = 0x07
+	authTypeRMCPPlus = 0x06
+	nextHeader       = 0x07
+
+	payloadIPMI                = 0x00
+	payloadOpenSessionRequest  = 0x10
+	payloadOpenSessionResponse = 0x11
+	payloadRAKP1               = 0x12
+	payloadRAKP2               = 0x13
+	payloadRAKP3               = 0x14
+	payloadRAKP4               = 0x15
+
+	payloadEncrypted     = 0x80
+	payloadAuthenticated = 0x40
+	payloadTypeMask      = 0x3f
+
+	// RAKP-HMAC-SHA1, HMAC-SHA1-96 and AES-CBC-128, cipher suite 3
+	algoAuthRAKPHMACSHA1    = 0x01
+	algoIntegrityHMACSHA196 = 0x01
+	algoConfAESCBC128       = 0x01
+
+	authCodeLength = 12
+)
+
+// sessionKeys - Keys of an active session, derived from the SIK
+type sessionKeys struct {
+	// integrity key K1
+	k1 []byte
+	// confidentiality key, the first 16 bytes of K2
+	aesKey []byte
+}
+
+// packet - An RMCP+ packet
+type packet struct {
+	// one of payload*, without the encrypted and authenticated flags
+	payloadType byte
+	sessionID   uint32
+	seq         uint32
+	payload     []byte
+}
+
+// hmacSHA1 - HMAC-SHA1 over the concatenation of data
+func hmacSHA1(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(sha1.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+// le32 - Little endian representation of v
+func le32(v uint32) []byte {
+	return binary.LittleEndian.AppendUint32(nil, v)
+}
+
+// deriveKeys - Derive session keys from the session integrity key
+func deriveKeys(sik []byte) *sessionKeys {
+	return &sessionKeys{
+		k1:     hmacSHA1(sik, bytes.Repeat([]byte{1}, 20)),
+		aesKey: hmacSHA1(sik, bytes.Repeat([]byte{2}, 20))[:16],
+	}
+}
+
+// encrypt - AES-CBC-128 with random IV and the confidentiality trailer
+func encrypt(key []byte, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	padLen := (aes.BlockSize - (len(data)+1)%aes.BlockSize) % aes.BlockSize
+	plain := append([]byte{}, data...)
+	for i := 1; i <= padLen; i++ {
+		plain = append(plain, byte(i))
+	}
+	plain = append(plain, byte(padLen))
+
+	out := make([]byte, aes.BlockSize+len(plain))
+	_, err = rand.Read(out[:aes.BlockSize])
+	if err != nil {
+		return nil, err
+	}
+	cipher.NewCBCEncrypter(block, out[:aes.BlockSize]).CryptBlocks(out[aes.BlockSize:], plain)
+	return out, nil
+}
+
+// decrypt - Reverse of encrypt
+func decrypt(key []byte, data []byte) ([]byte, error) {
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("Encrypted payload has invalid length %d", len(data))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(plain, data[aes.BlockSize:])
+
+	padLen := int(plain[len(plain)-1])
+	if padLen >= aes.BlockSize {
+		return nil, fmt.Errorf("Encrypted payload has invalid padding")
+	}
+	return plain[:len(plain)-1-padLen], nil
+}
+
+// encodePacket - Encode the packet, protected with the session keys if not nil
+func encodePacket(p *packet, keys *sessionKeys) ([]byte, error) {
+	buf := []byte{rmcpVersion, 0, rmcpSeqNoAck, rmcpClassIPMI}
+	hdr := len(buf)
+
+	payloadType := p.payloadType
+	payload := p.payload
+	if keys != nil {
+		payloadType |= payloadEncrypted | payloadAuthenticated
+		var err error
+		payload, err = encrypt(keys.aesKey, payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buf = append(buf, authTypeRMCPPlus, payloadType)
+	buf = binary.LittleEndian.AppendUint32(buf, p.sessionID)
+	buf = binary.LittleEndian.AppendUint32(buf, p.seq)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(payload)))
+	buf = append(buf, payload...)
+
+	if keys != nil {
+		// Integrity pad, so the authenticated part is a multiple of 4 bytes
+		padLen := (4 - (len(buf)-hdr+2)%4) % 4
+		buf = append(buf, bytes.Repeat([]byte{0xff}, padLen)...)
+		buf = append(buf, byte(padLen), nextHeader)
+		buf = append(buf, hmacSHA1(keys.k1, buf[hdr:])[:authCodeLength]...)
+	}
+	return buf, nil
+}
+
+// decodePacket - Decode a received packet. keys returns the keys of a session ID,
+// nil if the session is unknown.
+func decodePacket(b []byte, keys func(uint32) *sessionKeys) (*packet, error) {
+	if len(b) < 4 || b[0] != rmcpVersion || b[3] != rmcpClassIPMI {
+		return nil, fmt.Errorf("Not an RMCP IPMI packet")
+	}
+	h := b[4:]
+	if len(h) < 12 {
+		return nil, fmt.Errorf("Packet too short")
+	}
+	if h[0] != authTypeRMCPPlus {
+		return nil, fmt.Errorf("Only RMCP+ sessions are supported, got auth type %d", h[0])
+	}
+
+	p := &packet{
+		payloadType: h[1] & payloadTypeMask,
+		sessionID:   binary.LittleEndian.Uint32(h[2:6]),
+		seq:         binary.LittleEndian.Uint32(h[6:10]),
+	}
+	length := int(binary.LittleEndian.Uint16(h[10:12]))
+	if len(h) < 12+length {
+		return nil, fmt.Errorf("Payload exceeds packet")
+	}
+	p.payload = h[12 : 12+length]
+
+	if h[1]&(payloadAuthenticated|payloadEncrypted) == 0 {
+		return p, nil
+	}
+	k := keys(p.sessionID)
+	if k == nil {
+		return nil, fmt.Errorf("Protected packet for unknown session %08x", p.sessionID)
+	}
+
+	if h[1]&payloadAuthenticated != 0 {
+		if len(h) < 12+length+2+authCodeLength || (len(h)-authCodeLength)%4 != 0 {
+			return nil, fmt.Errorf("Session trailer has invalid length")
+		}
+		authCode := h[len(h)-authCodeLength:]
+		if !hmac.Equal(authCode, hmacSHA1(k.k1, h[:len(h)-authCodeLength])[:authCodeLength]) {
+			return nil, fmt.Errorf("Packet has wrong integrity check value")
+		}
+	}
+	if h[1]&payloadEncrypted != 0 {
+		payload, err := decrypt(k.aesKey, p.payload)
+		if err != nil {
+			return nil, err
+		}
+		p.payload = payload
+	}
+	return p, nil
+}
+
+// checksum - Two's complement checksum of IPMI messages
+func checksum(b []byte) byte {
+	var sum byte
+	for _, v := range b {
+		sum += v
+	}
+	return -sum
+}
+
+// encodeMessage - IPMI LAN message, see IPMI v2.0 spec table 13-8
+func encodeMessage(rsAddr byte, netFn byte, rqAddr byte, rqSeq byte, cmd byte, data []byte) []byte {
+	msg := []byte{rsAddr, netFn << 2, 0, rqAddr, rqSeq << 2, cmd}
+	msg[2] = checksum(msg[:2])
+	msg = append(msg, data...)
+	return append(msg, checksum(msg[3:]))
+}
+
+// message - A decoded IPMI LAN message
+type message struct {
+	netFn byte
+	rqSeq byte
+	cmd   byte
+	data  []byte
+}
+
+// decodeMessage - Decode and verify an IPMI LAN message
+func decodeMessage(b []byte) (*message, error) {
+	if len(b) < 7 {
+		return nil, fmt.Errorf("IPMI message too short")
+	}
+	if checksum(b[:2]) != b[2] || checksum(b[3:len(b)-1]) != b[len(b)-1] {
+		return nil, fmt.Errorf("IPMI message has wrong checksum")
+	}
+	return &message{
+		netFn: b[1] >> 2,
+		rqSeq: b[4] >> 2,
+		cmd:   b[5],
+		data:  b[6 : len(b)-1],
+	}, nil
+}
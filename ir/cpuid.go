@@ -0,0 +1,109 @@
+package ir
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/9elements/autorev/tracelog"
+)
+
+// CPUID output registers in the order of PRead.Regs
+var cpuidRegs = []string{"eax", "ebx", "ecx", "edx"}
+
+// CPUIDFeature - A single bit of a CPUID output register
+type CPUIDFeature struct {
+	Leaf    uint
+	Subleaf uint
+	// Index into PRead.Regs
+	Reg int
+	Bit uint
+}
+
+// String - The firmware option name of the feature bit, e.g. cpuid_7_0_ebx_5
+// Using it as option name makes LoopToIR branch on the feature bit.
+func (f CPUIDFeature) String() string {
+	return fmt.Sprintf("cpuid_%x_%x_%s_%d", f.Leaf, f.Subleaf, cpuidRegs[f.Reg], f.Bit)
+}
+
+// ConvertToC - Expression evaluating to 1 if the feature bit is set
+func (f CPUIDFeature) ConvertToC() string {
+	return fmt.Sprintf("((cpuid_ext(0x%x, 0x%x).%s >> %d) & 1)", f.Leaf, f.Subleaf, cpuidRegs[f.Reg], f.Bit)
+}
+
+// Value - Returns the state of the feature bit in a CPUID read
+func (f CPUIDFeature) Value(p PRead) (uint64, bool) {
+	if p.Type != CPUID || p.Address != f.Leaf || p.Subleaf != f.Subleaf {
+		return 0, false
+	}
+	return uint64(p.Regs[f.Reg]>>f.Bit) & 1, true
+}
+
+// ParseCPUIDFeature - Parse a firmware option name of the form cpuid_LEAF_SUBLEAF_REG_BIT
+// LEAF and SUBLEAF are hex, BIT is decimal.
+func ParseCPUIDFeature(name string) (*CPUIDFeature, error) {
+	parts := strings.Split(name, "_")
+	if len(parts) != 5 || parts[0] != "cpuid" {
+		return nil, fmt.Errorf("%s isn't a CPUID feature", name)
+	}
+	var f CPUIDFeature
+	leaf, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		return nil, err
+	}
+	subleaf, err := strconv.ParseUint(parts[2], 16, 32)
+	if err != nil {
+		return nil, err
+	}
+	f.Leaf, f.Subleaf = uint(leaf), uint(subleaf)
+	f.Reg = -1
+	for i := range cpuidRegs {
+		if cpuidRegs[i] == parts[3] {
+			f.Reg = i
+		}
+	}
+	if f.Reg == -1 {
+		return nil, fmt.Errorf("Unknown CPUID register %s", parts[3])
+	}
+	bit, err := strconv.ParseUint(parts[4], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	if bit > 31 {
+		return nil, fmt.Errorf("CPUID bit %d out of range", bit)
+	}
+	f.Bit = uint(bit)
+	return &f, nil
+}
+
+// conditionToC - Compare a firmware option against a value
+// CPUID feature options are replaced by a test of the feature bit.
+func conditionToC(name string, value uint64) string {
+	if f, err := ParseCPUIDFeature(name); err == nil {
+		return f.ConvertToC() + " == " + strconv.FormatUint(value, 10)
+	}
+	return name + " == " + strconv.FormatUint(value, 10)
+}
+
+// CPUIDFeatureOptions - Returns the state of the named feature bits as firmware options
+// The value is taken from the first CPUID read of the leaf and subleaf. Features
+// that have never been read are omitted.
+func CPUIDFeatureOptions(tles []tracelog.TraceLogEntry, names []string) (map[string]uint64, error) {
+	options := map[string]uint64{}
+	for _, name := range names {
+		f, err := ParseCPUIDFeature(name)
+		if err != nil {
+			return nil, err
+		}
+		for i := range tles {
+			if tles[i].Type != int(tracelog.CPUID) {
+				continue
+			}
+			if v, ok := f.Value(IRNewPrimitiveRead(tles[i])); ok {
+				options[name] = v
+				break
+			}
+		}
+	}
+	return options, nil
+}
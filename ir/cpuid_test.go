@@ -0,0 +1,70 @@
+package ir
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/9elements/autorev/tracelog"
+)
+
+func TestCPUIDConvertToC(t *testing.T) {
+	tle := tracelog.TraceLogEntry{Type: int(tracelog.CPUID), Inout: true, Address: 7,
+		Subleaf: 0, Regs: [4]uint32{0, 0x029c6fbf, 0x40000000, 0xbc000400}}
+	p := IRNewPrimitiveRead(tle)
+	want := "cpuid_ext(0x00000007, 0x00000000); // eax 0x00000000 ebx 0x029c6fbf ecx 0x40000000 edx 0xbc000400\n"
+	if got := p.ConvertToC(); got != want {
+		t.Errorf("PRead.ConvertToC() = %v, want %v", got, want)
+	}
+
+	f, err := ParseCPUIDFeature("cpuid_7_0_ebx_5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := f.Value(p); !ok || v != 1 {
+		t.Errorf("Got feature bit %d %v, want 1", v, ok)
+	}
+	p.Subleaf = 1
+	if _, ok := f.Value(p); ok {
+		t.Errorf("Feature matched wrong subleaf")
+	}
+	if f.String() != "cpuid_7_0_ebx_5" {
+		t.Errorf("Got name %s", f.String())
+	}
+}
+
+func TestConditionToC(t *testing.T) {
+	tests := []struct {
+		name  string
+		value uint64
+		want  string
+	}{
+		{"FspmUpd.Foo", 1, "FspmUpd.Foo == 1"},
+		{"cpuid_1_0_ecx_1f", 0, "cpuid_1_0_ecx_1f == 0"},
+		{"cpuid_80000001_0_edx_29", 1, "((cpuid_ext(0x80000001, 0x0).edx >> 29) & 1) == 1"},
+		{"cpuid_7_1_eax_4", 0, "((cpuid_ext(0x7, 0x1).eax >> 4) & 1) == 0"},
+	}
+	for _, tt := range tests {
+		if got := conditionToC(tt.name, tt.value); got != tt.want {
+			t.Errorf("conditionToC(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCPUIDFeatureOptions(t *testing.T) {
+	tles := []tracelog.TraceLogEntry{
+		{Type: int(tracelog.IO), Address: 0x80, Value: 1, AccessSize: 8},
+		{Type: int(tracelog.CPUID), Inout: true, Address: 1, Regs: [4]uint32{0x906ea, 0, 0x7ffafbff, 0xbfebfbff}},
+		{Type: int(tracelog.CPUID), Inout: true, Address: 7, Subleaf: 0, Regs: [4]uint32{0, 0x029c6fbf, 0, 0}},
+	}
+	got, err := CPUIDFeatureOptions(tles, []string{"cpuid_1_0_ecx_31", "cpuid_7_0_ebx_1", "cpuid_7_1_eax_4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]uint64{"cpuid_1_0_ecx_31": 0, "cpuid_7_0_ebx_1": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, want %v", got, want)
+	}
+	if _, err := CPUIDFeatureOptions(tles, []string{"cpuid_7_0_xmm_1"}); err == nil {
+		t.Errorf("Expected error for unknown register")
+	}
+}
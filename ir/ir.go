@@ -2,7 +2,6 @@ package ir
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/9elements/autorev/mesh"
@@ -25,6 +24,8 @@ const (
 	MSR
 	CPUID
 	PCI
+	MEM64
+	STRIO
 )
 
 type ir interface {
@@ -48,6 +49,12 @@ type PRead struct {
 	Value uint64
 	// 8, 16, 32, 64 bit
 	AccessSize uint
+	// CPUID only: ECX input
+	Subleaf uint
+	// CPUID only: EAX, EBX, ECX, EDX output
+	Regs [4]uint32
+	// STRIO only: number of elements, Value is the buffer address
+	Count uint
 }
 
 func (p PRead) GetType() IRType {
@@ -69,6 +76,8 @@ type PWrite struct {
 	Value uint64
 	// 8, 16, 32, 64 bit
 	AccessSize uint
+	// STRIO only: number of elements, Value is the buffer address
+	Count uint
 }
 
 func (p PWrite) GetType() IRType {
@@ -99,22 +108,34 @@ func (c CRMW) GetRank() uint {
 	return 2
 }
 
+// primitiveType - Map a tracelog LineType to a PrimtiveType
+func primitiveType(t int) PrimtiveType {
+	switch t {
+	case int(tracelog.IO):
+		return IO
+	case int(tracelog.CPUID):
+		return CPUID
+	case int(tracelog.MSR):
+		return MSR
+	case int(tracelog.PCI):
+		return PCI
+	case int(tracelog.MEM64):
+		return MEM64
+	case int(tracelog.STRIO):
+		return STRIO
+	}
+	return MEM32
+}
+
 func IRNewPrimitiveRead(e tracelog.TraceLogEntry) PRead {
 	var p PRead
 	p.AccessSize = e.AccessSize
 	p.Address = e.Address
 	p.Value = e.Value
-	if e.Type == int(tracelog.IO) {
-		p.Type = IO
-	} else if e.Type == int(tracelog.CPUID) {
-		p.Type = CPUID
-	} else if e.Type == int(tracelog.MSR) {
-		p.Type = MSR
-	} else if e.Type == int(tracelog.PCI) {
-		p.Type = PCI
-	} else if e.Type == int(tracelog.MEM32) {
-		p.Type = MEM32
-	}
+	p.Subleaf = e.Subleaf
+	p.Regs = e.Regs
+	p.Count = e.Count
+	p.Type = primitiveType(e.Type)
 	return p
 }
 
@@ -123,17 +144,8 @@ func IRNewPrimitiveWrite(e tracelog.TraceLogEntry) PWrite {
 	p.AccessSize = e.AccessSize
 	p.Address = e.Address
 	p.Value = e.Value
-	if e.Type == int(tracelog.IO) {
-		p.Type = IO
-	} else if e.Type == int(tracelog.CPUID) {
-		p.Type = CPUID
-	} else if e.Type == int(tracelog.MSR) {
-		p.Type = MSR
-	} else if e.Type == int(tracelog.PCI) {
-		p.Type = PCI
-	} else if e.Type == int(tracelog.MEM32) {
-		p.Type = MEM32
-	}
+	p.Count = e.Count
+	p.Type = primitiveType(e.Type)
 	return p
 }
 
@@ -209,7 +221,7 @@ func LoopToIR(ident int, start *mesh.MeshNode, end *mesh.MeshNode) string {
 						if !isfirst {
 							ret += " && "
 						}
-						ret += k + " == " + strconv.FormatUint(v, 10)
+						ret += conditionToC(k, v)
 						isfirst = false
 
 					}
@@ -248,7 +260,7 @@ func LoopToIR(ident int, start *mesh.MeshNode, end *mesh.MeshNode) string {
 							if !isfirst {
 								ret += " && "
 							}
-							ret += k + " == " + strconv.FormatUint(v, 10)
+							ret += conditionToC(k, v)
 							isfirst = false
 
 						}
@@ -5,6 +5,10 @@ import "fmt"
 func (p PRead) ConvertToC() string {
 	if p.Type == MEM32 {
 		return fmt.Sprintf("read%d((void *)0x%08x); // 0x%08x\n", p.AccessSize, p.Address, p.Value)
+	} else if p.Type == MEM64 {
+		return fmt.Sprintf("read%d((void *)0x%016x); // 0x%016x\n", p.AccessSize, p.Address, p.Value)
+	} else if p.Type == STRIO {
+		return fmt.Sprintf("ins%s(0x%04x, (void *)0x%08x, %d);\n", ioSuffix(p.AccessSize), p.Address, p.Value, p.Count)
 	} else if p.Type == IO {
 		var a string
 		if p.AccessSize == 8 {
@@ -18,7 +22,8 @@ func (p PRead) ConvertToC() string {
 	} else if p.Type == MSR {
 		return fmt.Sprintf("rdmsr(0x%08x); // 0x%016x\n", p.Address, p.Value)
 	} else if p.Type == CPUID {
-		return fmt.Sprintf("cpuid(0x%08x); // 0x%016x\n", p.Address, p.Value)
+		return fmt.Sprintf("cpuid_ext(0x%08x, 0x%08x); // eax 0x%08x ebx 0x%08x ecx 0x%08x edx 0x%08x\n",
+			p.Address, p.Subleaf, p.Regs[0], p.Regs[1], p.Regs[2], p.Regs[3])
 	} else if p.Type == PCI {
 		b := (p.Address >> 20) & 0xff
 		d := (p.Address >> 15) & 0x1f
@@ -32,6 +37,10 @@ func (p PRead) ConvertToC() string {
 func (p PWrite) ConvertToC() string {
 	if p.Type == MEM32 {
 		return fmt.Sprintf("write%d((void *)0x%08x, 0x%08x);\n", p.AccessSize, p.Address, p.Value)
+	} else if p.Type == MEM64 {
+		return fmt.Sprintf("write%d((void *)0x%016x, 0x%016x);\n", p.AccessSize, p.Address, p.Value)
+	} else if p.Type == STRIO {
+		return fmt.Sprintf("outs%s(0x%04x, (const void *)0x%08x, %d);\n", ioSuffix(p.AccessSize), p.Address, p.Value, p.Count)
 	} else if p.Type == IO {
 		var a string
 		if p.AccessSize == 8 {
@@ -64,6 +73,11 @@ func (c CRMW) ConvertToC() string {
 		ret += fmt.Sprintf("tmp &= ~0x%08x\n", c.AndMask)
 		ret += fmt.Sprintf("tmp |= 0x%08x\n", c.OrMask)
 		ret += fmt.Sprintf("write%d((void *)0x%08x, tmp);\n", c.AccessSize, c.Address)
+	} else if c.Type == MEM64 {
+		ret += fmt.Sprintf("uint%d_t tmp = read%d((void *)0x%016x);\n", c.AccessSize, c.AccessSize, c.Address)
+		ret += fmt.Sprintf("tmp &= ~0x%016x\n", c.AndMask)
+		ret += fmt.Sprintf("tmp |= 0x%016x\n", c.OrMask)
+		ret += fmt.Sprintf("write%d((void *)0x%016x, tmp);\n", c.AccessSize, c.Address)
 	} else if c.Type == IO {
 		var a string
 		if c.AccessSize == 8 {
@@ -99,3 +113,15 @@ func (c CRMW) ConvertToC() string {
 	ret += "}\n"
 	return ret
 }
+
+// ioSuffix - Suffix of the IO functions for the access size
+func ioSuffix(accessSize uint) string {
+	if accessSize == 8 {
+		return "b"
+	} else if accessSize == 16 {
+		return "w"
+	} else if accessSize == 32 {
+		return "l"
+	}
+	return ""
+}
@@ -59,6 +59,11 @@ func TestPRead_ConvertToC(t *testing.T) {
 			fields{MSR, 0x67, 0, 32},
 			"rdmsr(0x00000067); // 0x0000000000000000\n",
 		},
+		{
+			"read64",
+			fields{MEM64, 0x4000000000, 0x8000000000000001, 64},
+			"read64((void *)0x0000004000000000); // 0x8000000000000001\n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -131,6 +136,11 @@ func TestPWrite_ConvertToC(t *testing.T) {
 			"wrmsr",
 			fields{MSR, 0x67, 0, 32},
 			"{\n\tmsr_t msr = {.lo = 0x00000000, .hi = 0x00000000};\n\twrmsr(0x00000067, msr);\n}\n",
+		},
+		{
+			"write16_64",
+			fields{MEM64, 0x100000000, 0xaabb, 16},
+			"write16((void *)0x0000000100000000, 0x000000000000aabb);\n",
 		}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -177,3 +187,14 @@ func TestCRMW_ConvertToC(t *testing.T) {
 		})
 	}
 }
+
+func TestStringIO_ConvertToC(t *testing.T) {
+	r := PRead{Type: STRIO, Address: 0x1f0, Value: 0x7c00, Count: 256, AccessSize: 16}
+	if got, want := r.ConvertToC(), "insw(0x01f0, (void *)0x00007c00, 256);\n"; got != want {
+		t.Errorf("PRead.ConvertToC() = %v, want %v", got, want)
+	}
+	w := PWrite{Type: STRIO, Address: 0x3f8, Value: 0x1000, Count: 12, AccessSize: 8}
+	if got, want := w.ConvertToC(), "outsb(0x03f8, (const void *)0x00001000, 12);\n"; got != want {
+		t.Errorf("PWrite.ConvertToC() = %v, want %v", got, want)
+	}
+}
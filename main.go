@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"sync"
 
 	"github.com/9elements/autorev/config"
 	"github.com/9elements/autorev/ir"
@@ -22,6 +23,8 @@ func main() {
 	fifoDevicePath := flag.String("fifo", "", "The fifos to communicate with a debug target (appends .in and .out)")
 	collectNewTrace := flag.Bool("runtrace", false, "Collect a new tracelog")
 	addNewTrace := flag.Bool("newtrace", false, "Add new tracelogs based on FirmwareOption config")
+	dryRun := flag.Bool("dryrun", false, "Only print the number of tests and their coverage. To be used with -newtrace")
+	initDB := flag.Bool("initdb", false, "Create the database schema or migrate an existing database to the current version")
 	addNewConfig := flag.Bool("newConfig", false, "Add new default config")
 	newConfigName := flag.String("newConfigName", "", "Name of the new default config")
 	newConfigFile := flag.String("newConfigFile", "", "Path to nee default config file")
@@ -29,7 +32,17 @@ func main() {
 	buildAst := flag.Bool("buildast", false, "Generates an AST from all successful tracelogs")
 	genCCode := flag.String("genCcode", "", "Path to generated C code from AST. To be used with -buildAst")
 	genDot := flag.String("genDot", "", "Path to generated dot file from AST. To be used with -buildAst")
+	window := flag.String("window", "", "Only use entries of the named capture window. To be used with -buildAst")
+	importLog := flag.String("importlog", "", "Import a captured serial log file as tracelog")
+	importConfigFile := flag.String("importconfig", "", "Path to the config blob the imported log was captured with. To be used with -importlog")
+	testID := flag.Int("testid", 0, "Existing test id. To be used with -importlog and -addfakes")
+	addFakes := flag.String("addfakes", "", "Add BL_FAKE rules from a YAML file to the test given by -testid")
+	dumpLog := flag.Int("dumplog", 0, "Print the complete console log of the given test id")
+	timing := flag.Int("timing", 0, "Print the time spent between POST codes and capture windows of the given test id")
 
+	dut := flag.String("dut", "", "Name of the DUT profile to use. To be used with -runtrace")
+	campaign := flag.String("campaign", "", "Name of the campaign to work on, created by -newtrace if missing")
+	listCampaigns := flag.Bool("campaigns", false, "List all campaigns")
 	verbose := flag.Bool("verbose", false, "Be verbose")
 
 	flag.Parse()
@@ -40,7 +53,7 @@ func main() {
 		panic(err.Error())
 	}
 
-	if len(cfg.Database.Password) == 0 {
+	if cfg.Database.Driver != "file" && len(cfg.Database.Password) == 0 {
 		log.Printf("Database password is empty in config.yml!")
 		log.Printf("Did you set up a database already?")
 	}
@@ -53,7 +66,47 @@ func main() {
 
 	defer test.Close()
 
-	if *addNewConfig { // Add a new Config aka FirmwareOption BLOB to DB
+	if len(*campaign) > 0 {
+		// Only adding new tests may create a campaign, the settings of an
+		// existing campaign replace those of config.yml
+		cfg, err = test.UseCampaign(*campaign, cfg, *addNewTrace && !*dryRun)
+		if err != nil {
+			log.Printf("%v\n", err)
+			return
+		}
+	}
+
+	// Built after the campaign snapshot replaced the settings of config.yml
+	fakeRules, err := tracelog.FakeRulesFromConfig(cfg.TraceLog.FakeRules)
+	if err != nil {
+		log.Printf("%v\n", err)
+		return
+	}
+
+	filters, err := tracelog.FiltersFromConfig(cfg.TraceLog.Filters)
+	if err != nil {
+		log.Printf("%v\n", err)
+		return
+	}
+
+	if *initDB { // Create or update the database schema
+
+		err = test.Migrate()
+		if err != nil {
+			log.Printf("%v\n", err)
+			os.Exit(1)
+		}
+	} else if *listCampaigns { // Print all campaigns
+
+		campaigns, err := test.GetCampaigns()
+		if err != nil {
+			log.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		for _, c := range campaigns {
+			fmt.Printf("%4d %-32s %s\n", c.ID, c.Name, c.Created.Format("2006-01-02 15:04:05"))
+		}
+	} else if *addNewConfig { // Add a new Config aka FirmwareOption BLOB to DB
 
 		// Open config file
 		f, err := ioutil.ReadFile(*newConfigFile)
@@ -69,82 +122,188 @@ func main() {
 		}
 	} else if *collectAllTraces { // Process all tracelogs not run yet
 
-		tl, err := tracelog.CreateTraceLog(*devTTYDevicePath, *baudTTYDevice, *fifoDevicePath, cfg)
-		if err != nil {
-			log.Printf(err.Error())
+		// One worker per DUT profile, the serial and dutcontrol sections form the only DUT otherwise
+		duts := cfg.TraceLog.Duts
+		if len(duts) == 0 {
+			duts = []config.Dut{{Name: "default", Serial: cfg.TraceLog.Serial, DutControl: cfg.TraceLog.DutControl}}
+		} else if len(*devTTYDevicePath) > 0 || len(*fifoDevicePath) > 0 {
+			log.Printf("-dev and -fifo can't be used with DUT profiles")
 			os.Exit(1)
 		}
-		tl.SetVerbose(*verbose)
-		// The complete mesh
 
-		for {
-			id, err := test.GetNextTest()
+		var wg sync.WaitGroup
+		for _, dut := range duts {
+			tl, err := tracelog.CreateTraceLog(*devTTYDevicePath, *baudTTYDevice, *fifoDevicePath, cfg.ForDut(dut))
 			if err != nil {
-				log.Printf("%v\n", err)
-				return
-			}
-			if id == 0 {
-				log.Println("All test have been run.")
-				break
-			}
-			config, err := test.GetConfig(test.LatestTestID)
-			if err != nil {
-				log.Printf("%v\n", err)
-				return
-			}
-			err = test.SetTestInProgress()
-			if err != nil {
-				log.Printf("%v\n", err)
-				return
+				log.Printf("DUT %s: %v\n", dut.Name, err)
+				os.Exit(1)
 			}
+			tl.SetVerbose(*verbose)
+			// Every worker needs its own latest test
+			test := test.Worker()
+			name := dut.Name
 
-			tles, err := tl.CollectNewTracelog(config)
-			if err != nil {
-				log.Printf("%v\n", err)
-				err = test.SetTestFailed()
-				if err != nil {
-					log.Printf("%v\n", err)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					id, err := test.ClaimNextTest(name)
+					if err != nil {
+						log.Printf("DUT %s: %v\n", name, err)
+						return
+					}
+					if id == 0 {
+						log.Printf("DUT %s: All test have been run.\n", name)
+						break
+					}
+					config, err := test.GetConfig(test.LatestTestID)
+					if err != nil {
+						log.Printf("DUT %s: %v\n", name, err)
+						err = test.SetTestFailed()
+						if err != nil {
+							log.Printf("DUT %s: %v\n", name, err)
+						}
+						continue
+					}
+					err = test.AddFakeRules(fakeRules)
+					if err != nil {
+						log.Printf("DUT %s: %v\n", name, err)
+						err = test.SetTestFailed()
+						if err != nil {
+							log.Printf("DUT %s: %v\n", name, err)
+						}
+						continue
+					}
+					rules, err := test.GetFakeRules(test.LatestTestID)
+					if err != nil {
+						log.Printf("DUT %s: %v\n", name, err)
+						err = test.SetTestFailed()
+						if err != nil {
+							log.Printf("DUT %s: %v\n", name, err)
+						}
+						continue
+					}
+					tl.SetFakeRules(rules)
+					err = test.SetFilters(filters)
+					if err != nil {
+						log.Printf("DUT %s: %v\n", name, err)
+						err = test.SetTestFailed()
+						if err != nil {
+							log.Printf("DUT %s: %v\n", name, err)
+						}
+						continue
+					}
+					tl.SetFilters(filters)
+
+					tl.SetTestID(test.LatestTestID)
+					stream := tracelog.NewStream(test.TraceWriter(), cfg.Database.BatchSize, 0)
+					tl.SetStream(stream)
+					_, err = tl.CollectNewTracelog(config)
+					stats, streamErr := stream.Close()
+					log.Printf("DUT %s: Wrote %s\n", name, stats)
+					if err == nil {
+						err = streamErr
+					}
+					if logErr := test.SetCompleteLog(tl.CompleteLog()); logErr != nil {
+						log.Printf("DUT %s: %v\n", name, logErr)
+					}
+					if logErr := test.SetAttempts(tl.Attempts()); logErr != nil {
+						log.Printf("DUT %s: %v\n", name, logErr)
+					}
+					if logErr := test.AddCommands(tl.Commands()); logErr != nil {
+						log.Printf("DUT %s: %v\n", name, logErr)
+					}
+					if err != nil {
+						log.Printf("DUT %s: %v\n", name, err)
+						err = test.SetTestFailed()
+						if err != nil {
+							log.Printf("DUT %s: %v\n", name, err)
+						}
+						continue
+					}
+					err = test.SetTestSuccessful()
+					if err != nil {
+						log.Printf("DUT %s: %v\n", name, err)
+					}
 				}
-				continue
-			}
-			err = test.SetTestSuccessful()
-			if err != nil {
-				log.Printf("%v\n", err)
-			}
-			log.Printf("Writing to DB..")
-			err = test.WriteSetIntoDB(tles)
-			if err != nil {
-				log.Printf("%v", err)
-				return
-			}
-			log.Println("Done.")
+			}()
 		}
+		wg.Wait()
 
 	} else if *collectNewTrace { // Collect a single new tracelog that haven't run yet
 
-		tl, err := tracelog.CreateTraceLog(*devTTYDevicePath, *baudTTYDevice, *fifoDevicePath, cfg)
+		dutCfg := cfg
+		dutName := "default"
+		if len(*dut) > 0 {
+			found := false
+			for i := range cfg.TraceLog.Duts {
+				if cfg.TraceLog.Duts[i].Name == *dut {
+					dutCfg = cfg.ForDut(cfg.TraceLog.Duts[i])
+					found = true
+				}
+			}
+			if !found {
+				log.Printf("Unknown DUT %s\n", *dut)
+				os.Exit(1)
+			}
+			dutName = *dut
+		}
+		tl, err := tracelog.CreateTraceLog(*devTTYDevicePath, *baudTTYDevice, *fifoDevicePath, dutCfg)
 		if err != nil {
-			log.Printf(err.Error())
+			log.Printf("%v\n", err)
 			os.Exit(1)
 		}
 		tl.SetVerbose(*verbose)
 
-		_, err = test.GetNextTest()
+		id, err := test.ClaimNextTest(dutName)
 		if err != nil {
 			log.Printf("%v\n", err)
 			return
 		}
+		if id == 0 {
+			log.Println("All test have been run.")
+			return
+		}
 		config, err := test.GetConfig(test.LatestTestID)
 		if err != nil {
 			log.Printf("%v\n", err)
 			return
 		}
-		err = test.SetTestInProgress()
+		err = test.AddFakeRules(fakeRules)
 		if err != nil {
 			log.Printf("%v\n", err)
 			return
 		}
-		tles, err := tl.CollectNewTracelog(config)
+		rules, err := test.GetFakeRules(test.LatestTestID)
+		if err != nil {
+			log.Printf("%v\n", err)
+			return
+		}
+		tl.SetFakeRules(rules)
+		err = test.SetFilters(filters)
+		if err != nil {
+			log.Printf("%v\n", err)
+			return
+		}
+		tl.SetFilters(filters)
+		tl.SetTestID(test.LatestTestID)
+		stream := tracelog.NewStream(test.TraceWriter(), cfg.Database.BatchSize, 0)
+		tl.SetStream(stream)
+		_, err = tl.CollectNewTracelog(config)
+		stats, streamErr := stream.Close()
+		log.Printf("Wrote %s\n", stats)
+		if err == nil {
+			err = streamErr
+		}
+		if logErr := test.SetCompleteLog(tl.CompleteLog()); logErr != nil {
+			log.Printf("%v\n", logErr)
+		}
+		if logErr := test.SetAttempts(tl.Attempts()); logErr != nil {
+			log.Printf("%v\n", logErr)
+		}
+		if logErr := test.AddCommands(tl.Commands()); logErr != nil {
+			log.Printf("%v\n", logErr)
+		}
 		if err != nil {
 			log.Printf("%v\n", err)
 			err = test.SetTestFailed()
@@ -157,13 +316,11 @@ func main() {
 		if err != nil {
 			log.Printf("%v\n", err)
 		}
-		log.Printf("Writing %d lines into the DB..", len(tles))
-		err = test.WriteSetIntoDB(tles)
+		tles, err := test.FetchTraceLogEntriesFromDB(test.LatestTestID)
 		if err != nil {
 			log.Printf("%v", err)
 			return
 		}
-		log.Println("Done.")
 		f, err := os.Create("generatedC.c")
 		if err != nil {
 			log.Printf("%v", err)
@@ -184,50 +341,188 @@ func main() {
 			}
 		}
 
-	} else if *addNewTrace { // Generate recursive tracelogs to be run based on config.yml
+	} else if len(*addFakes) > 0 { // Add fake rules to a single test
+
+		if *testID <= 0 {
+			log.Printf("-addfakes needs -testid\n")
+			os.Exit(1)
+		}
+		cfgRules, err := config.GetFakeRules(*addFakes)
+		if err != nil {
+			log.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		rules, err := tracelog.FakeRulesFromConfig(cfgRules)
+		if err != nil {
+			log.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		test.SetLatestTestID(*testID)
+		err = test.AddFakeRules(rules)
+		if err != nil {
+			log.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		log.Printf("Added %d fake rules to test %d\n", len(rules), *testID)
+	} else if *dumpLog > 0 { // Print the raw console log of a test
+
+		completeLog, err := test.GetCompleteLog(*dumpLog)
+		if err != nil {
+			log.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(completeLog)
+	} else if *timing > 0 { // Print where the firmware spent its time
+
+		tles, err := test.FetchTraceLogEntriesFromDB(*timing)
+		if err != nil {
+			log.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(tracelog.FormatTimingReport(tracelog.TimingReport(tles, tracelog.PostCodePort)))
+	} else if len(*importLog) > 0 { // Import a tracelog captured without autorev
+
+		tles, err := tracelog.ImportTracelogFile(*importLog, cfg, *verbose)
+		if err != nil {
+			log.Printf("%v\n", err)
+			os.Exit(1)
+		}
+
+		if *testID > 0 {
+			test.SetLatestTestID(*testID)
+		} else {
+			var blob []byte
+			if len(*importConfigFile) > 0 {
+				blob, err = ioutil.ReadFile(*importConfigFile)
+			} else {
+				blob, err = test.GetDefaultConfig(cfg.TraceLog.OptionsDefaultTable)
+			}
+			if err != nil {
+				log.Printf("%v\n", err)
+				os.Exit(1)
+			}
+			err = test.GenNewTest("", cfg, blob)
+			if err != nil {
+				log.Printf("%v\n", err)
+				os.Exit(1)
+			}
+			log.Printf("Created test id %d\n", test.LatestTestID)
+		}
+
+		log.Printf("Writing %d lines into the DB..", len(tles))
+		err = test.WriteSetIntoDB(tles)
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+		err = test.SetTestSuccessful()
+		if err != nil {
+			log.Printf("%v\n", err)
+		}
+		log.Println("Done.")
+	} else if *addNewTrace { // Generate tracelogs to be run based on config.yml
 
 		blob, err := test.GetDefaultConfig(cfg.TraceLog.OptionsDefaultTable)
 		if err != nil {
-			log.Printf(err.Error())
+			log.Printf("%v\n", err)
 			os.Exit(1)
 		}
 
-		cnt, err := test.GenRecursiveNewTestsFromCfg("", cfg, blob, 0)
+		cnt, err := test.GenNewTestsFromCfg(cfg, blob, *dryRun)
 		if err != nil {
-			log.Printf(err.Error())
+			log.Printf("%v\n", err)
 			os.Exit(1)
 		}
-		log.Printf("Added %d new tracelogs to be tested\n", cnt)
+		if *dryRun {
+			log.Printf("Would add %d new tracelogs to be tested\n", cnt)
+		} else {
+			log.Printf("Added %d new tracelogs to be tested\n", cnt)
+		}
 	} else if *buildAst {
 		testIds, err := test.FetchSuccessfulTraceLogIDFromDB()
 		if err != nil {
-			log.Printf(err.Error())
+			log.Printf("%v\n", err)
 			os.Exit(1)
 		}
 		if len(testIds) == 0 {
 			log.Printf("No tests in database, aborting\n")
 			os.Exit(1)
 		}
-		var m = mesh.Mesh{Start: mesh.MeshNode{Id: 0, Hash: "0"}}
+		// Accesses filtered on one test must be removed from all others
+		var allFilters []tracelog.Filter
+		for t := range testIds {
+			testFilters, err := test.GetFilters(testIds[t])
+			if err != nil {
+				log.Printf("%v\n", err)
+				os.Exit(1)
+			}
+			allFilters = append(allFilters, testFilters...)
+		}
+		for i := range allFilters {
+			log.Printf("Filtered: %s\n", allFilters[i].String())
+		}
+
+		var m = mesh.Mesh{Start: mesh.MeshNode{Id: 0, Hash: "0"}, Volatile: mesh.AccessSet{}}
+
+		// Accesses differing between runs of the same config must not fork the mesh
+		groups, err := test.FetchSuccessfulTestsByConfigFromDB()
+		if err != nil {
+			log.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		for _, g := range groups {
+			if len(g) < 2 {
+				continue
+			}
+			var runs [][]tracelog.TraceLogEntry
+			for _, id := range g {
+				tles, err := test.FetchTraceLogEntriesFromDB(id)
+				if err != nil {
+					log.Printf("%v\n", err)
+					os.Exit(1)
+				}
+				tles = tracelog.ApplyFilters(tles, allFilters)
+				if len(*window) > 0 {
+					tles = tracelog.SelectWindow(tles, *window)
+				}
+				runs = append(runs, tles)
+			}
+			m.Volatile.Merge(mesh.FindVolatile(runs))
+		}
+		for _, k := range m.Volatile.Keys() {
+			log.Printf("Volatile: %s\n", k.String())
+		}
 
 		for t := range testIds {
 			log.Printf("Merging test id %d\n", testIds[t])
 			options, err := test.GetFirmwareOptionsFromConfigBLOBs(cfg, testIds[t])
 			if err != nil {
-				log.Printf(err.Error())
+				log.Printf("%v\n", err)
 				os.Exit(1)
 			}
-			log.Printf("%v\n", options)
 			tles, err := test.FetchTraceLogEntriesFromDB(testIds[t])
 			if err != nil {
-				log.Printf(err.Error())
+				log.Printf("%v\n", err)
+				os.Exit(1)
+			}
+			features, err := ir.CPUIDFeatureOptions(tles, cfg.TraceLog.CPUIDFeatures)
+			if err != nil {
+				log.Printf("%v\n", err)
 				os.Exit(1)
 			}
+			for k, v := range features {
+				options[k] = v
+			}
+			log.Printf("%v\n", options)
+			tles = tracelog.ApplyFilters(tles, allFilters)
+			if len(*window) > 0 {
+				tles = tracelog.SelectWindow(tles, *window)
+			}
 			log.Printf(" %d trace log entries\n", len(tles))
 
 			err = m.InsertTraceLogIntoMesh(tles, options)
 			if err != nil {
-				log.Printf(err.Error())
+				log.Printf("%v\n", err)
 				os.Exit(1)
 			}
 		}
@@ -239,7 +534,7 @@ func main() {
 		if len(*genCCode) > 0 {
 			err := ioutil.WriteFile(*genCCode, []byte(ir.MeshToIR(&m)), 0644)
 			if err != nil {
-				log.Printf(err.Error())
+				log.Printf("%v\n", err)
 				os.Exit(1)
 			}
 		}
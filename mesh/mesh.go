@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/9elements/autorev/tracelog"
 
@@ -37,6 +38,8 @@ type Mesh struct {
 	Nodes  []*MeshNode
 	// IDcounter, increment on new MeshNode
 	ID uint64
+	// Volatile accesses are hashed without their value
+	Volatile AccessSet
 }
 
 // a Branch is a Mesh, but only has one path
@@ -574,6 +577,15 @@ func (m *Mesh) MeshNodeFromTraceLogEntry(tle tracelog.TraceLogEntry) (*MeshNode,
 	var a = m.CreateNode(false)
 	a.TLE = tle
 
+	// Timing differs on every run and must not split nodes
+	tle.TSC = 0
+	tle.Received = time.Time{}
+	// Values of volatile accesses differ on every run as well
+	if m.Volatile.Match(&tle) {
+		tle.Value = 0
+		tle.Regs = [4]uint32{}
+		tle.Count = 0
+	}
 	sha := sha256.Sum256([]byte(fmt.Sprintf("%v", tle)))
 	a.Hash = ""
 	for _, i := range sha {
@@ -589,15 +601,23 @@ func (m *Mesh) MeshNodeFromTraceLogEntry(tle tracelog.TraceLogEntry) (*MeshNode,
 // It first creates a new branch and then merges the branch into the mesh using LCS
 // Every created node on the branch gets assigned a FirmwareOptions slice
 // On merge FirmwareOptions slices are also merged
+// Repeated volatile accesses, like polling a status register, are collapsed
+// into one node as the number of iterations differs on every run.
 func (m *Mesh) InsertTraceLogIntoMesh(tles []tracelog.TraceLogEntry, FirmwareOptions map[string]uint64) error {
-	var b = Mesh{Start: MeshNode{Id: 0}, ID: 1}
+	var b = Mesh{Start: MeshNode{Id: 0}, ID: 1, Volatile: m.Volatile}
 
 	// Create a branch
+	var last *MeshNode
 	for i := range tles {
 		n, err := b.MeshNodeFromTraceLogEntry(tles[i])
 		if err != nil {
 			return err
 		}
+		if last != nil && last.Hash == n.Hash && b.Volatile.Match(&tles[i]) {
+			b.Nodes = b.Nodes[:len(b.Nodes)-1]
+			continue
+		}
+		last = n
 		// make a deep copy
 		newmap := map[string]uint64{}
 		for k, v := range FirmwareOptions {
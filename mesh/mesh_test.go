@@ -2,6 +2,7 @@ package mesh
 
 import (
 	"testing"
+	"time"
 
 	"github.com/9elements/autorev/tracelog"
 )
@@ -533,3 +534,28 @@ func TestFirmwareOptionMerge(t *testing.T) {
 		}
 	}
 }
+
+func TestMeshNodeCPUIDSubleaf(t *testing.T) {
+	var m = Mesh{Start: MeshNode{Id: 0}, ID: 1}
+	leaf0, _ := m.MeshNodeFromTraceLogEntry(tracelog.TraceLogEntry{IP: 1, Type: int(tracelog.CPUID), Inout: true, Address: 7, Regs: [4]uint32{1, 2, 3, 4}})
+	leaf1, _ := m.MeshNodeFromTraceLogEntry(tracelog.TraceLogEntry{IP: 1, Type: int(tracelog.CPUID), Inout: true, Address: 7, Subleaf: 1, Regs: [4]uint32{1, 2, 3, 4}})
+	other, _ := m.MeshNodeFromTraceLogEntry(tracelog.TraceLogEntry{IP: 1, Type: int(tracelog.CPUID), Inout: true, Address: 7, Regs: [4]uint32{1, 2, 3, 5}})
+	if leaf0.Hash == leaf1.Hash {
+		t.Errorf("CPUID subleaves have the same hash")
+	}
+	if leaf0.Hash == other.Hash {
+		t.Errorf("CPUID reads with different output have the same hash")
+	}
+}
+
+func TestMeshNodeIgnoresTiming(t *testing.T) {
+	var m = Mesh{Start: MeshNode{Id: 0}, ID: 1}
+	a, _ := m.MeshNodeFromTraceLogEntry(tracelog.TraceLogEntry{IP: 1, Type: int(tracelog.IO), Address: 0x80, Value: 1, AccessSize: 8, TSC: 100, Received: time.Now()})
+	b, _ := m.MeshNodeFromTraceLogEntry(tracelog.TraceLogEntry{IP: 1, Type: int(tracelog.IO), Address: 0x80, Value: 1, AccessSize: 8, TSC: 200})
+	if a.Hash != b.Hash {
+		t.Errorf("Timing changed the node hash")
+	}
+	if a.TLE.TSC != 100 {
+		t.Errorf("Node lost the TSC")
+	}
+}
@@ -0,0 +1,163 @@
+package mesh
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/9elements/autorev/tracelog"
+	lcs "github.com/yudai/golcs"
+)
+
+// AccessKey - Identifies an access by type, direction, address and the
+// instruction doing it
+type AccessKey struct {
+	Type    int
+	Inout   bool
+	Address uint
+	IP      uint
+}
+
+// String - Convert AccessKey into a readable string
+func (k AccessKey) String() string {
+	dir := "O"
+	if k.Inout {
+		dir = "I"
+	}
+	return fmt.Sprintf("%s %s %08x at %08x", tracelog.ConvertFromType(k.Type), dir, k.Address, k.IP)
+}
+
+// AccessSet - A set of accesses, e.g. the volatile reads that return different
+// values between runs of the same config like timer or ACPI PM counter reads
+type AccessSet map[AccessKey]bool
+
+func accessKey(tle *tracelog.TraceLogEntry) AccessKey {
+	return AccessKey{Type: tle.Type, Inout: tle.Inout, Address: tle.Address, IP: tle.IP}
+}
+
+// Match - Returns true if the access of the entry is part of the set
+func (v AccessSet) Match(tle *tracelog.TraceLogEntry) bool {
+	return v[accessKey(tle)]
+}
+
+// Merge - Add all accesses of o
+func (v AccessSet) Merge(o AccessSet) {
+	for k := range o {
+		v[k] = true
+	}
+}
+
+// Subtract - Remove all accesses of o
+func (v AccessSet) Subtract(o AccessSet) {
+	for k := range o {
+		delete(v, k)
+	}
+}
+
+// Intersects - Returns true if an access is part of both sets
+func (v AccessSet) Intersects(o AccessSet) bool {
+	for k := range o {
+		if v[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// Registers - Returns the accesses without the instructions doing them
+func (v AccessSet) Registers() AccessSet {
+	r := AccessSet{}
+	for k := range v {
+		k.IP = 0
+		r[k] = true
+	}
+	return r
+}
+
+// Keys - Returns the accesses sorted by type, address, direction and IP
+func (v AccessSet) Keys() []AccessKey {
+	var keys []AccessKey
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Type != keys[j].Type {
+			return keys[i].Type < keys[j].Type
+		}
+		if keys[i].Address != keys[j].Address {
+			return keys[i].Address < keys[j].Address
+		}
+		if keys[i].Inout != keys[j].Inout {
+			return !keys[i].Inout
+		}
+		return keys[i].IP < keys[j].IP
+	})
+	return keys
+}
+
+// shape - Everything of an entry but the value, used to align runs
+func shape(tle *tracelog.TraceLogEntry) string {
+	return fmt.Sprintf("%d %v %x %d %x %x %s", tle.Type, tle.Inout, tle.Address, tle.AccessSize, tle.IP, tle.Subleaf, tle.Window)
+}
+
+// align - Returns the pairs of entries of a and b with the same shape in order
+func align(a, b []tracelog.TraceLogEntry) []lcs.IndexPair {
+	shapesA := make([]interface{}, len(a))
+	for i := range a {
+		shapesA[i] = shape(&a[i])
+	}
+	shapesB := make([]interface{}, len(b))
+	for i := range b {
+		shapesB[i] = shape(&b[i])
+	}
+	return lcs.New(shapesA, shapesB).IndexPairs()
+}
+
+// sameResult - Returns true if both entries read or wrote the same
+func sameResult(x, y *tracelog.TraceLogEntry) bool {
+	return x.Value == y.Value && x.Regs == y.Regs && x.Count == y.Count
+}
+
+// DiffAccesses - Aligns two traces and returns the accesses that differ
+// Aligned accesses with different values differ, as well as accesses only
+// present in one trace, like a code path only taken by one of them.
+func DiffAccesses(a, b []tracelog.TraceLogEntry) AccessSet {
+	v := AccessSet{}
+
+	matchedA := make([]bool, len(a))
+	matchedB := make([]bool, len(b))
+	for _, p := range align(a, b) {
+		matchedA[p.Left] = true
+		matchedB[p.Right] = true
+		if !sameResult(&a[p.Left], &b[p.Right]) {
+			v[accessKey(&a[p.Left])] = true
+		}
+	}
+	for i := range a {
+		if !matchedA[i] {
+			v[accessKey(&a[i])] = true
+		}
+	}
+	for i := range b {
+		if !matchedB[i] {
+			v[accessKey(&b[i])] = true
+		}
+	}
+	return v
+}
+
+// FindVolatile - Aligns runs of the same config and returns the reads that
+// return different values at the same IP
+// Writes and accesses missing in one of the runs aren't volatile, they would
+// hide differences between configs in the whole mesh.
+func FindVolatile(runs [][]tracelog.TraceLogEntry) AccessSet {
+	v := AccessSet{}
+	for i := 1; i < len(runs); i++ {
+		for _, p := range align(runs[0], runs[i]) {
+			x, y := &runs[0][p.Left], &runs[i][p.Right]
+			if x.Inout && !sameResult(x, y) {
+				v[accessKey(x)] = true
+			}
+		}
+	}
+	return v
+}
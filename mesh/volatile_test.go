@@ -0,0 +1,72 @@
+package mesh
+
+import (
+	"testing"
+
+	"github.com/9elements/autorev/tracelog"
+)
+
+func TestFindVolatile(t *testing.T) {
+	timer := tracelog.TraceLogEntry{IP: 2, Type: int(tracelog.IO), Inout: true, Address: 0x408, AccessSize: 32}
+	status := tracelog.TraceLogEntry{IP: 3, Type: int(tracelog.MEM32), Inout: true, Address: 0xfed40000, AccessSize: 32}
+	post := tracelog.TraceLogEntry{IP: 4, Type: int(tracelog.IO), Address: 0x80, Value: 0x10, AccessSize: 8}
+	seed := tracelog.TraceLogEntry{IP: 5, Type: int(tracelog.MEM32), Address: 0xfed40010, AccessSize: 32}
+
+	var runA, runB []tracelog.TraceLogEntry
+	timer.Value = 100
+	seed.Value = 1
+	runA = append(runA, timer, status, post, seed)
+	timer.Value = 250
+	seed.Value = 2
+	runB = append(runB, timer, status, status, post, seed)
+
+	v := FindVolatile([][]tracelog.TraceLogEntry{runA, runB})
+	if len(v) != 1 {
+		t.Errorf("Wrong number of volatile accesses: %v", v.Keys())
+	}
+	if !v.Match(&timer) {
+		t.Errorf("Timer read with different values isn't volatile")
+	}
+	if v.Match(&status) {
+		t.Errorf("Additional polling read is volatile")
+	}
+	if v.Match(&seed) {
+		t.Errorf("Write with different values is volatile")
+	}
+	if v.Match(&post) {
+		t.Errorf("POST code is volatile")
+	}
+	other := timer
+	other.IP = 6
+	if v.Match(&other) {
+		t.Errorf("Timer read at another IP is volatile")
+	}
+
+	if len(FindVolatile([][]tracelog.TraceLogEntry{runA})) != 0 {
+		t.Errorf("Single run has volatile accesses")
+	}
+}
+
+func TestInsertTraceLogVolatile(t *testing.T) {
+	var m = Mesh{Start: MeshNode{Id: 0, Hash: "0"}, ID: 1}
+	m.Volatile = AccessSet{AccessKey{Type: int(tracelog.IO), Inout: true, Address: 0x408, IP: 2}: true}
+
+	read := func(v uint64) tracelog.TraceLogEntry {
+		return tracelog.TraceLogEntry{IP: 2, Type: int(tracelog.IO), Inout: true, Address: 0x408, Value: v, AccessSize: 32}
+	}
+	post := tracelog.TraceLogEntry{IP: 4, Type: int(tracelog.IO), Address: 0x80, Value: 0x10, AccessSize: 8}
+
+	m.InsertTraceLogIntoMesh([]tracelog.TraceLogEntry{read(1), read(2), read(3), post}, map[string]uint64{"a": 0})
+	m.InsertTraceLogIntoMesh([]tracelog.TraceLogEntry{read(7), post}, map[string]uint64{"a": 1})
+
+	if len(m.Nodes) != 2 {
+		t.Errorf("Wrong node count in mesh: %d", len(m.Nodes))
+		return
+	}
+	if len(m.Start.Next) != 1 {
+		t.Errorf("Volatile read forked the mesh")
+	}
+	if len(m.Start.Next[0].FirmwareOptions) != 2 {
+		t.Errorf("FirmwareOptions haven't been merged")
+	}
+}
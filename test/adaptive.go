@@ -0,0 +1,371 @@
+package test
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/9elements/autorev/config"
+	"github.com/9elements/autorev/mesh"
+	"github.com/9elements/autorev/tracelog"
+)
+
+// TracedTest - Options and trace of a successful test
+type TracedTest struct {
+	Options map[string]uint64
+	Trace   []tracelog.TraceLogEntry
+}
+
+// Influence - What changes in the trace when options differ from the default config
+type Influence struct {
+	// Baseline - A test of the unchanged default config has been traced
+	Baseline bool
+	// Swept - Options with a traced test changing only this option
+	Swept map[string]bool
+	// Touched - Accesses differing from the baseline when the option changes,
+	// including those only differing in combination with other options
+	Touched map[string]mesh.AccessSet
+}
+
+// Influential - Returns true if changing the option changes the trace
+func (in Influence) Influential(name string) bool {
+	return len(in.Touched[name]) > 0
+}
+
+// changedOptions - Returns the names of options differing from defaults, sorted
+func changedOptions(defaults map[string]uint64, options map[string]uint64) []string {
+	var names []string
+	for name, val := range defaults {
+		if v, ok := options[name]; ok && v != val {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AnalyseInfluence - Compares the traces of tests against the trace of the default config
+// Accesses differing from the baseline in a test changing a single option are
+// touched by that option. Accesses differing in a test changing several
+// options that none of them touches alone are interactions and touched by all
+// of them. Volatile accesses are ignored.
+func AnalyseInfluence(defaults map[string]uint64, tests []TracedTest, volatile mesh.AccessSet) Influence {
+	in := Influence{Swept: map[string]bool{}, Touched: map[string]mesh.AccessSet{}}
+	for name := range defaults {
+		in.Touched[name] = mesh.AccessSet{}
+	}
+
+	changed := make([][]string, len(tests))
+	baseline := -1
+	for i := range tests {
+		changed[i] = changedOptions(defaults, tests[i].Options)
+		if len(changed[i]) == 0 && baseline < 0 {
+			baseline = i
+		}
+	}
+	if baseline < 0 {
+		return in
+	}
+	in.Baseline = true
+
+	diffs := make([]mesh.AccessSet, len(tests))
+	for i := range tests {
+		if len(changed[i]) == 0 {
+			continue
+		}
+		diffs[i] = mesh.DiffAccesses(tests[baseline].Trace, tests[i].Trace)
+		diffs[i].Subtract(volatile)
+	}
+
+	single := map[string]mesh.AccessSet{}
+	for name := range defaults {
+		single[name] = mesh.AccessSet{}
+	}
+	for i := range tests {
+		if len(changed[i]) != 1 {
+			continue
+		}
+		in.Swept[changed[i][0]] = true
+		single[changed[i][0]].Merge(diffs[i])
+		in.Touched[changed[i][0]].Merge(diffs[i])
+	}
+
+	for i := range tests {
+		if len(changed[i]) < 2 {
+			continue
+		}
+		emergent := mesh.AccessSet{}
+		emergent.Merge(diffs[i])
+		for _, name := range changed[i] {
+			emergent.Subtract(single[name])
+		}
+		for _, name := range changed[i] {
+			in.Touched[name].Merge(emergent)
+		}
+	}
+	return in
+}
+
+// Groups - Influential options grouped transitively by the accesses they touch
+// Options of different groups don't touch the same registers, so their
+// combinations don't need to be tested. Every group is sorted by name.
+func (in Influence) Groups() [][]string {
+	var names []string
+	for name := range in.Touched {
+		if in.Influential(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	parent := make([]int, len(names))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	registers := make([]mesh.AccessSet, len(names))
+	for i, name := range names {
+		registers[i] = in.Touched[name].Registers()
+	}
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			if registers[i].Intersects(registers[j]) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	var groups [][]string
+	index := map[int]int{}
+	for i, name := range names {
+		root := find(i)
+		g, ok := index[root]
+		if !ok {
+			g = len(groups)
+			index[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], name)
+	}
+	return groups
+}
+
+// PlanAdaptive - Returns the options of the next round of tests
+// As long as the baseline or a sweep of an option is missing, the default
+// config and every option at every value with all others at their default are
+// returned. Afterwards the combinations of the options of every group with more
+// than one option are returned, all of them if the group isn't larger than
+// strength, a covering array of that strength otherwise.
+func PlanAdaptive(cfg config.Config, defaults map[string]uint64, in Influence) ([]map[string]uint64, error) {
+	opts := cfg.TraceLog.VariableFirmareOptions
+	gen := cfg.TraceLog.Generation
+
+	withDefaults := func() map[string]uint64 {
+		options := map[string]uint64{}
+		for name, val := range defaults {
+			options[name] = val
+		}
+		return options
+	}
+
+	var plan []map[string]uint64
+	swept := in.Baseline
+	for _, opt := range opts {
+		if !in.Swept[opt.Name] && opt.Max > opt.Min {
+			swept = false
+		}
+	}
+	if !swept {
+		plan = append(plan, withDefaults())
+		for _, opt := range opts {
+			for v := opt.Min; v <= opt.Max && v >= opt.Min; v++ {
+				if v == defaults[opt.Name] {
+					continue
+				}
+				options := withDefaults()
+				options[opt.Name] = v
+				plan = append(plan, options)
+			}
+		}
+		return plan, nil
+	}
+
+	strength := gen.Strength
+	if strength <= 0 {
+		strength = 3
+	}
+	byName := map[string]int{}
+	for i, opt := range opts {
+		byName[opt.Name] = i
+	}
+	for _, group := range in.Groups() {
+		if len(group) < 2 {
+			continue
+		}
+		sizes := make([]int, len(group))
+		for i, name := range group {
+			opt := opts[byName[name]]
+			sizes[i] = int(opt.Max-opt.Min) + 1
+		}
+		strategy := GenerateFull
+		if len(group) > strength {
+			strategy = GenerateTWise
+		}
+		tests, err := GenerateTests(sizes, strategy, strength, 0, gen.Seed)
+		if err != nil {
+			return nil, err
+		}
+		for _, test := range tests {
+			options := withDefaults()
+			for i, name := range group {
+				options[name] = opts[byName[name]].Min + uint64(test[i])
+			}
+			plan = append(plan, options)
+		}
+	}
+	return plan, nil
+}
+
+// FormatInfluence - Formats which options are inert, influential or not swept yet
+func FormatInfluence(cfg config.Config, in Influence) string {
+	var lines []string
+	if !in.Baseline {
+		lines = append(lines, "Baseline of the default config not traced yet")
+	}
+	for _, opt := range cfg.TraceLog.VariableFirmareOptions {
+		switch {
+		case in.Influential(opt.Name):
+			var keys []string
+			for _, k := range in.Touched[opt.Name].Keys() {
+				keys = append(keys, k.String())
+			}
+			lines = append(lines, fmt.Sprintf("Option %s is influential, touches %s", opt.Name, strings.Join(keys, ", ")))
+		case in.Swept[opt.Name]:
+			lines = append(lines, fmt.Sprintf("Option %s is inert", opt.Name))
+		default:
+			lines = append(lines, fmt.Sprintf("Option %s not swept yet", opt.Name))
+		}
+	}
+	for _, group := range in.Groups() {
+		if len(group) > 1 {
+			lines = append(lines, fmt.Sprintf("Options %s touch the same registers", strings.Join(group, ", ")))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fetchTracedTests - Returns the options and filtered traces of all successful
+// tests of the campaign and the accesses differing between repeated runs
+func (t *test) fetchTracedTests(cfg config.Config) ([]TracedTest, mesh.AccessSet, error) {
+	groups, err := t.FetchSuccessfulTestsByConfigFromDB()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Accesses filtered on one test must be removed from all others
+	var filters []tracelog.Filter
+	for _, g := range groups {
+		for _, id := range g {
+			testFilters, err := t.store.Filters(id)
+			if err != nil {
+				return nil, nil, err
+			}
+			filters = append(filters, testFilters...)
+		}
+	}
+
+	var tests []TracedTest
+	volatile := mesh.AccessSet{}
+	for _, g := range groups {
+		blob, err := t.store.Config(g[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		options := optionsFromBlob(cfg, blob)
+
+		var runs [][]tracelog.TraceLogEntry
+		for _, id := range g {
+			tles, err := t.store.Entries(id)
+			if err != nil {
+				return nil, nil, err
+			}
+			runs = append(runs, tracelog.ApplyFilters(tles, filters))
+		}
+		volatile.Merge(mesh.FindVolatile(runs))
+		tests = append(tests, TracedTest{Options: options, Trace: runs[0]})
+	}
+	return tests, volatile, nil
+}
+
+// genAdaptiveTests - Adds the next round of adaptive tests not queued yet
+// Every round analyses the successful tests of the campaign, so running
+// -newtrace again after the tests have been traced refines the plan until no
+// new tests are left.
+func (t *test) genAdaptiveTests(cfg config.Config, blob []byte, dryRun bool) (uint, error) {
+	tests, volatile, err := t.fetchTracedTests(cfg)
+	if err != nil {
+		return 0, err
+	}
+	defaults := optionsFromBlob(cfg, blob)
+
+	in := AnalyseInfluence(defaults, tests, volatile)
+	log.Printf("Analysed %d traced configs\n%s\n", len(tests), FormatInfluence(cfg, in))
+
+	plan, err := PlanAdaptive(cfg, defaults, in)
+	if err != nil {
+		return 0, err
+	}
+
+	configs, err := t.store.Configs(t.campaign.ID)
+	if err != nil {
+		return 0, err
+	}
+	queued := map[string]bool{}
+	for _, c := range configs {
+		queued[string(c)] = true
+	}
+
+	var blobs [][]byte
+	var names []string
+	for _, options := range plan {
+		blobcopy, name := blobWithOptions(cfg, blob, options)
+		if queued[string(blobcopy)] {
+			continue
+		}
+		queued[string(blobcopy)] = true
+		blobs = append(blobs, blobcopy)
+		names = append(names, name)
+	}
+	if len(blobs) == 0 {
+		log.Printf("Adaptive generation converged, all %d planned configs are queued already\n", len(plan))
+		return 0, nil
+	}
+	log.Printf("Strategy %s planned %d configs, %d are new\n", GenerateAdaptive, len(plan), len(blobs))
+
+	repeat := cfg.TraceLog.Repeat
+	if repeat == 0 {
+		repeat = 1
+	}
+	if dryRun {
+		return uint(len(blobs)) * repeat, nil
+	}
+
+	var cnt uint
+	for i := range blobs {
+		for r := uint(0); r < repeat; r++ {
+			err := t.GenNewTest(names[i], cfg, blobs[i])
+			if err != nil {
+				return cnt, err
+			}
+			cnt++
+		}
+	}
+	return cnt, nil
+}
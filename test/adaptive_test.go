@@ -0,0 +1,131 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/9elements/autorev/config"
+	"github.com/9elements/autorev/mesh"
+	"github.com/9elements/autorev/tracelog"
+)
+
+// adaptiveConfig - Options A, B and C with values 0..1 and D with values 0..2
+func adaptiveConfig() config.Config {
+	var cfg config.Config
+	for i, name := range []string{"A", "B", "C", "D"} {
+		cfg.TraceLog.VariableFirmareOptions = append(cfg.TraceLog.VariableFirmareOptions, struct {
+			Name       string `yaml:"name"`
+			ByteOffset uint   `yaml:"byteoffset"`
+			BitWidth   uint   `yaml:"bitwidth"`
+			Min        uint64 `yaml:"min"`
+			Max        uint64 `yaml:"max"`
+		}{Name: name, ByteOffset: uint(i), BitWidth: 8, Max: 1})
+	}
+	cfg.TraceLog.VariableFirmareOptions[3].Max = 2
+	return cfg
+}
+
+// fakeFirmware - A and B write the same register, C another one, D is inert.
+// Setting A and D together takes an additional path. The timer is volatile.
+func fakeFirmware(o map[string]uint64, run uint) []tracelog.TraceLogEntry {
+	io := int(tracelog.IO)
+	tles := []tracelog.TraceLogEntry{
+		{Type: io, Address: 0x40, Inout: true, Value: uint64(run)},
+		{Type: io, Address: 0x80, Value: 0x10},
+		{Type: io, Address: 0xcf8, Value: o["A"] | o["B"]<<1},
+		{Type: io, Address: 0xcfc, Value: o["C"]},
+	}
+	if o["A"] == 1 && o["D"] == 2 {
+		tles = append(tles, tracelog.TraceLogEntry{Type: io, Address: 0x3f8, Value: 1})
+	}
+	return append(tles, tracelog.TraceLogEntry{Type: io, Address: 0x80, Value: 0x20})
+}
+
+// runPlan - Traces every planned config twice
+func runPlan(tests []TracedTest, volatile mesh.AccessSet, plan []map[string]uint64) ([]TracedTest, mesh.AccessSet) {
+	for _, o := range plan {
+		runs := [][]tracelog.TraceLogEntry{fakeFirmware(o, uint(len(tests))), fakeFirmware(o, uint(len(tests)+1))}
+		volatile.Merge(mesh.FindVolatile(runs))
+		tests = append(tests, TracedTest{Options: o, Trace: runs[0]})
+	}
+	return tests, volatile
+}
+
+func TestAdaptive(t *testing.T) {
+	cfg := adaptiveConfig()
+	defaults := map[string]uint64{"A": 0, "B": 0, "C": 0, "D": 0}
+
+	in := AnalyseInfluence(defaults, nil, mesh.AccessSet{})
+	if in.Baseline {
+		t.Errorf("Baseline found without tests")
+	}
+	plan, err := PlanAdaptive(cfg, defaults, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Baseline and 1 + 1 + 1 + 2 values differing from the default
+	if len(plan) != 6 {
+		t.Fatalf("Sweep has %d tests, expected 6", len(plan))
+	}
+	tests, volatile := runPlan(nil, mesh.AccessSet{}, plan)
+
+	in = AnalyseInfluence(defaults, tests, volatile)
+	if !in.Baseline || !in.Influential("A") || !in.Influential("B") || !in.Influential("C") {
+		t.Errorf("A, B and C must be influential: %v", in.Touched)
+	}
+	if in.Influential("D") || !in.Swept["D"] {
+		t.Errorf("D must be inert: %v", in.Touched["D"])
+	}
+	if in.Touched["A"][mesh.AccessKey{Type: int(tracelog.IO), Inout: true, Address: 0x40}] {
+		t.Errorf("Volatile access attributed to A")
+	}
+	groups := in.Groups()
+	if len(groups) != 2 || len(groups[0]) != 2 || groups[0][0] != "A" || groups[0][1] != "B" || groups[1][0] != "C" {
+		t.Fatalf("Wrong groups %v", groups)
+	}
+
+	plan, err = PlanAdaptive(cfg, defaults, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// All combinations of A and B, C and D at default
+	if len(plan) != 4 {
+		t.Fatalf("Planned %d tests, expected 4", len(plan))
+	}
+	for _, o := range plan {
+		if o["C"] != 0 || o["D"] != 0 {
+			t.Errorf("Inert or independent option changed in %v", o)
+		}
+	}
+
+	// The interaction of A and D only shows when both are changed
+	tests, volatile = runPlan(tests, volatile, []map[string]uint64{{"A": 1, "B": 0, "C": 0, "D": 2}})
+	in = AnalyseInfluence(defaults, tests, volatile)
+	if !in.Influential("D") {
+		t.Errorf("Interaction of A and D not attributed to D")
+	}
+	if in.Touched["A"].Intersects(in.Touched["C"]) {
+		t.Errorf("A and C touch the same accesses")
+	}
+	groups = in.Groups()
+	if len(groups) != 2 || len(groups[0]) != 3 || groups[0][2] != "D" {
+		t.Errorf("Wrong groups %v", groups)
+	}
+}
+
+func TestBlobWithOptions(t *testing.T) {
+	cfg := adaptiveConfig()
+	blob := []byte{0, 0, 0, 0, 0xff}
+	options := map[string]uint64{"A": 1, "D": 2}
+
+	blobcopy, name := blobWithOptions(cfg, blob, options)
+	if blob[0] != 0 {
+		t.Errorf("Blob has been modified")
+	}
+	if name != "A=1 D=2 " {
+		t.Errorf("Wrong name %q", name)
+	}
+	got := optionsFromBlob(cfg, blobcopy)
+	if got["A"] != 1 || got["B"] != 0 || got["D"] != 2 {
+		t.Errorf("Wrong options %v", got)
+	}
+}
@@ -0,0 +1,553 @@
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/9elements/autorev/tracelog"
+)
+
+// Operations of the file store journal
+const (
+	fileOpAddCampaign      = "addcampaign"
+	fileOpAddTest          = "addtest"
+	fileOpClaim            = "claim"
+	fileOpStatus           = "status"
+	fileOpAddDefaultConfig = "adddefault"
+	fileOpCompleteLog      = "completelog"
+	fileOpAttempts         = "attempts"
+	fileOpCommands         = "commands"
+	fileOpEntries          = "entries"
+	fileOpResetEntries     = "resetentries"
+	fileOpFakeRules        = "fakerules"
+	fileOpFilters          = "filters"
+)
+
+// fileRecord - A single line of the journal, only the fields of Op are set
+type fileRecord struct {
+	Op        string                   `json:"op"`
+	Test      int                      `json:"test,omitempty"`
+	Campaign  int                      `json:"campaign,omitempty"`
+	Time      time.Time                `json:"time"`
+	Status    Status                   `json:"status,omitempty"`
+	Name      string                   `json:"name,omitempty"`
+	Blob      []byte                   `json:"blob,omitempty"`
+	Snapshot  []byte                   `json:"snapshot,omitempty"`
+	Attempts  []tracelog.Attempt       `json:"attempts,omitempty"`
+	Commands  []tracelog.CommandResult `json:"commands,omitempty"`
+	Entries   []tracelog.TraceLogEntry `json:"entries,omitempty"`
+	FakeRules []tracelog.FakeRule      `json:"fakerules,omitempty"`
+	Filters   []tracelog.Filter        `json:"filters,omitempty"`
+}
+
+// fileTest - In memory state of a test, built by replaying the journal
+type fileTest struct {
+	status        Status
+	campaign      int
+	added         time.Time
+	started       time.Time
+	finished      time.Time
+	dut           string
+	config        []byte
+	defaultConfig string
+	completeLog   []byte
+	attempts      []tracelog.Attempt
+	commands      []tracelog.CommandResult
+	entries       []tracelog.TraceLogEntry
+	fakeRules     []tracelog.FakeRule
+	filters       []tracelog.Filter
+}
+
+// fileStore - Store in a single file, no database server needed
+// Every change is appended to the file as JSON line and the complete state
+// is kept in memory. The file is locked, so only one process can use it.
+type fileStore struct {
+	mu sync.Mutex
+	f  *os.File
+	// end of the last complete record
+	offset    int64
+	tests     map[int]*fileTest
+	defaults  map[string][]byte
+	campaigns []Campaign
+	lastID    int
+}
+
+func openFileStore(path string) (*fileStore, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("No path given for the file database")
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	err = lockFile(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s is in use by another process: %v", path, err)
+	}
+	s := &fileStore{
+		f:        f,
+		tests:    map[int]*fileTest{},
+		defaults: map[string][]byte{},
+	}
+	err = s.replay()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("Failed to read %s: %v", path, err)
+	}
+	return s, nil
+}
+
+// replay - Apply all records of the journal. A record torn by a crash at the
+// end of the file is dropped.
+func (s *fileStore) replay() error {
+	r := bufio.NewReader(s.f)
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if len(line) == 0 {
+			break
+		}
+
+		var rec fileRecord
+		torn := err == io.EOF
+		if !torn {
+			torn = json.Unmarshal(line, &rec) != nil
+			if torn {
+				// Only the last record can be torn
+				if _, err := r.Peek(1); err != io.EOF {
+					return fmt.Errorf("Invalid record at offset %d", offset)
+				}
+			}
+		}
+		if torn {
+			log.Printf("Dropping incomplete record at offset %d\n", offset)
+			err = s.f.Truncate(offset)
+			if err != nil {
+				return err
+			}
+			break
+		}
+		s.apply(&rec)
+		offset += int64(len(line))
+	}
+	s.offset = offset
+	_, err := s.f.Seek(offset, io.SeekStart)
+	return err
+}
+
+// apply - Update the in memory state, must hold mu
+func (s *fileStore) apply(rec *fileRecord) {
+	if rec.Op == fileOpAddDefaultConfig {
+		s.defaults[rec.Name] = rec.Blob
+		return
+	}
+	if rec.Op == fileOpAddCampaign {
+		s.campaigns = append(s.campaigns, Campaign{
+			ID:          rec.Campaign,
+			Name:        rec.Name,
+			Created:     rec.Time,
+			Snapshot:    rec.Snapshot,
+			DefaultBlob: rec.Blob,
+		})
+		return
+	}
+	if rec.Op == fileOpAddTest {
+		s.tests[rec.Test] = &fileTest{
+			campaign:      rec.Campaign,
+			added:         rec.Time,
+			config:        rec.Blob,
+			defaultConfig: rec.Name,
+		}
+		if rec.Test > s.lastID {
+			s.lastID = rec.Test
+		}
+		return
+	}
+
+	t, ok := s.tests[rec.Test]
+	if !ok {
+		log.Printf("Ignoring %s record of unknown test %d\n", rec.Op, rec.Test)
+		return
+	}
+	switch rec.Op {
+	case fileOpClaim:
+		t.status = StatusRunning
+		t.started = rec.Time
+		t.dut = rec.Name
+	case fileOpStatus:
+		t.status = rec.Status
+		if rec.Status == StatusRunning {
+			t.started = rec.Time
+		} else {
+			t.finished = rec.Time
+		}
+	case fileOpCompleteLog:
+		t.completeLog = rec.Blob
+	case fileOpAttempts:
+		t.attempts = rec.Attempts
+	case fileOpCommands:
+		t.commands = append(t.commands, rec.Commands...)
+	case fileOpEntries:
+		t.entries = append(t.entries, rec.Entries...)
+	case fileOpResetEntries:
+		t.entries = nil
+	case fileOpFakeRules:
+		t.fakeRules = append(t.fakeRules, rec.FakeRules...)
+	case fileOpFilters:
+		t.filters = rec.Filters
+	default:
+		log.Printf("Ignoring unknown record %s\n", rec.Op)
+	}
+}
+
+// commit - Append the record to the journal and apply it, must hold mu
+// The decoded record is applied, so the state doesn't share slices with the
+// caller and equals the state after a replay.
+func (s *fileStore) commit(rec fileRecord) error {
+	rec.Time = time.Now()
+	b, err := json.Marshal(&rec)
+	if err != nil {
+		return err
+	}
+	n, err := s.f.Write(append(b, '\n'))
+	if err != nil {
+		// Remove the partial record, later records would follow it
+		if terr := s.f.Truncate(s.offset); terr != nil {
+			log.Printf("Failed to remove partial record: %v\n", terr)
+		}
+		if _, serr := s.f.Seek(s.offset, io.SeekStart); serr != nil {
+			log.Printf("Failed to remove partial record: %v\n", serr)
+		}
+		return err
+	}
+	s.offset += int64(n)
+	var applied fileRecord
+	err = json.Unmarshal(b, &applied)
+	if err != nil {
+		return err
+	}
+	s.apply(&applied)
+	return nil
+}
+
+// test - Returns the test or an error, must hold mu
+func (s *fileStore) test(testID int) (*fileTest, error) {
+	t, ok := s.tests[testID]
+	if !ok {
+		return nil, fmt.Errorf("Test %d not found", testID)
+	}
+	return t, nil
+}
+
+// Migrate - The journal has no schema, unknown fields are ignored on replay
+func (s *fileStore) Migrate() error {
+	return nil
+}
+
+func (s *fileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// inCampaign - Returns true if campaign is 0 or the test belongs to it
+func (t *fileTest) inCampaign(campaign int) bool {
+	return campaign == 0 || t.campaign == campaign
+}
+
+// nextTest - Oldest new test of the campaign, must hold mu
+func (s *fileStore) nextTest(campaign int) int {
+	next := 0
+	for id, t := range s.tests {
+		if t.status != StatusNew || !t.inCampaign(campaign) {
+			continue
+		}
+		if next == 0 || t.added.Before(s.tests[next].added) ||
+			(t.added.Equal(s.tests[next].added) && id < next) {
+			next = id
+		}
+	}
+	return next
+}
+
+func (s *fileStore) NextTest(campaign int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nextTest(campaign), nil
+}
+
+func (s *fileStore) ClaimNextTest(campaign int, dut string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextTest(campaign)
+	if id == 0 {
+		return 0, nil
+	}
+	err := s.commit(fileRecord{Op: fileOpClaim, Test: id, Name: dut})
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+func (s *fileStore) SetStatus(testID int, status Status) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.test(testID); err != nil {
+		return err
+	}
+	return s.commit(fileRecord{Op: fileOpStatus, Test: testID, Status: status})
+}
+
+func (s *fileStore) AddTest(campaign int, configBlob []byte, defaultConfig string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.lastID + 1
+	err := s.commit(fileRecord{Op: fileOpAddTest, Test: id, Campaign: campaign, Name: defaultConfig, Blob: configBlob})
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+func (s *fileStore) Config(testID int) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.test(testID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), t.config...), nil
+}
+
+func (s *fileStore) SuccessfulTests(campaign int) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []int
+	for id, t := range s.tests {
+		if t.status == StatusSuccessful && t.inCampaign(campaign) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+func (s *fileStore) Configs(campaign int) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []int
+	for id, t := range s.tests {
+		if t.inCampaign(campaign) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	configs := make([][]byte, len(ids))
+	for i, id := range ids {
+		configs[i] = append([]byte(nil), s.tests[id].config...)
+	}
+	return configs, nil
+}
+
+func (s *fileStore) AddCampaign(c Campaign) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.campaigns {
+		if e.Name == c.Name {
+			return -1, fmt.Errorf("Campaign %s already exists", c.Name)
+		}
+	}
+	id := len(s.campaigns) + 1
+	err := s.commit(fileRecord{Op: fileOpAddCampaign, Campaign: id, Name: c.Name, Snapshot: c.Snapshot, Blob: c.DefaultBlob})
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+func (s *fileStore) CampaignByName(name string) (Campaign, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.campaigns {
+		if c.Name == name {
+			c.Snapshot = append([]byte(nil), c.Snapshot...)
+			c.DefaultBlob = append([]byte(nil), c.DefaultBlob...)
+			return c, nil
+		}
+	}
+	return Campaign{}, nil
+}
+
+func (s *fileStore) Campaigns() ([]Campaign, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Campaign(nil), s.campaigns...), nil
+}
+
+func (s *fileStore) AddDefaultConfig(name string, blob []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.defaults[name]; ok {
+		return fmt.Errorf("Default config %s already exists", name)
+	}
+	return s.commit(fileRecord{Op: fileOpAddDefaultConfig, Name: name, Blob: blob})
+}
+
+func (s *fileStore) DefaultConfig(name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	blob, ok := s.defaults[name]
+	if !ok {
+		return nil, fmt.Errorf("Default config %s not found", name)
+	}
+	return append([]byte(nil), blob...), nil
+}
+
+func (s *fileStore) SetCompleteLog(testID int, completeLog []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.test(testID); err != nil {
+		return err
+	}
+	return s.commit(fileRecord{Op: fileOpCompleteLog, Test: testID, Blob: completeLog})
+}
+
+func (s *fileStore) CompleteLog(testID int) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.test(testID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), t.completeLog...), nil
+}
+
+func (s *fileStore) SetAttempts(testID int, attempts []tracelog.Attempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.test(testID); err != nil {
+		return err
+	}
+	return s.commit(fileRecord{Op: fileOpAttempts, Test: testID, Attempts: attempts})
+}
+
+func (s *fileStore) Attempts(testID int) ([]tracelog.Attempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.test(testID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]tracelog.Attempt(nil), t.attempts...), nil
+}
+
+func (s *fileStore) AddCommands(testID int, commands []tracelog.CommandResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.test(testID); err != nil {
+		return err
+	}
+	return s.commit(fileRecord{Op: fileOpCommands, Test: testID, Commands: commands})
+}
+
+func (s *fileStore) WriteEntries(testID int, entries []tracelog.TraceLogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.test(testID); err != nil {
+		return err
+	}
+	return s.commit(fileRecord{Op: fileOpEntries, Test: testID, Entries: entries})
+}
+
+func (s *fileStore) ResetEntries(testID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.test(testID); err != nil {
+		return err
+	}
+	return s.commit(fileRecord{Op: fileOpResetEntries, Test: testID})
+}
+
+func (s *fileStore) Entries(testID int) ([]tracelog.TraceLogEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.test(testID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]tracelog.TraceLogEntry(nil), t.entries...), nil
+}
+
+func (s *fileStore) AddFakeRules(testID int, rules []tracelog.FakeRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.test(testID); err != nil {
+		return err
+	}
+	return s.commit(fileRecord{Op: fileOpFakeRules, Test: testID, FakeRules: rules})
+}
+
+func (s *fileStore) FakeRules(testID int) ([]tracelog.FakeRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.test(testID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]tracelog.FakeRule(nil), t.fakeRules...), nil
+}
+
+func (s *fileStore) SetFilters(testID int, filters []tracelog.Filter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.test(testID); err != nil {
+		return err
+	}
+	return s.commit(fileRecord{Op: fileOpFilters, Test: testID, Filters: filters})
+}
+
+func (s *fileStore) Filters(testID int) ([]tracelog.Filter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.test(testID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]tracelog.Filter(nil), t.filters...), nil
+}
@@ -0,0 +1,248 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/9elements/autorev/tracelog"
+)
+
+func TestFileStoreReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autorev.db")
+	s, err := openFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blob := []byte{1, 2, 3}
+	err = s.AddDefaultConfig("kbl", blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.AddTest(0, blob, "kbl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the store must not share the blob with the caller
+	blob[0] = 9
+
+	claimed, err := s.ClaimNextTest(0, "dut0")
+	if err != nil || claimed != id {
+		t.Fatalf("Claimed %d, %v", claimed, err)
+	}
+	s.WriteEntries(id, []tracelog.TraceLogEntry{{Type: int(tracelog.IO), Address: 0x80, Value: 1}})
+	s.ResetEntries(id)
+	s.WriteEntries(id, []tracelog.TraceLogEntry{{Type: int(tracelog.IO), Address: 0x80, Value: 2, Regs: [4]uint32{1, 2, 3, 4}}})
+	s.SetFilters(id, []tracelog.Filter{{Type: int(tracelog.MEM32), Address: 0xfed40000}})
+	s.SetStatus(id, StatusSuccessful)
+	s.Close()
+
+	s, err = openFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	cfg, _ := s.Config(id)
+	if len(cfg) != 3 || cfg[0] != 1 {
+		t.Errorf("Wrong config %v", cfg)
+	}
+	tles, _ := s.Entries(id)
+	if len(tles) != 1 || tles[0].Value != 2 || tles[0].Regs[3] != 4 {
+		t.Errorf("Wrong entries %v", tles)
+	}
+	filters, _ := s.Filters(id)
+	if len(filters) != 1 || filters[0].Address != 0xfed40000 {
+		t.Errorf("Wrong filters %v", filters)
+	}
+	ids, _ := s.SuccessfulTests(0)
+	if len(ids) != 1 || ids[0] != id {
+		t.Errorf("Wrong successful tests %v", ids)
+	}
+	next, _ := s.AddTest(0, nil, "kbl")
+	if next != id+1 {
+		t.Errorf("Test id %d reused", next)
+	}
+}
+
+func TestFileStoreTornRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autorev.db")
+	s, err := openFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := s.AddTest(0, []byte{1}, "kbl")
+	s.Close()
+
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"op":"entries","test":1,"entr`)
+	f.Close()
+
+	s, err = openFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tles, _ := s.Entries(id)
+	if len(tles) != 0 {
+		t.Errorf("Torn record has been applied")
+	}
+	s.WriteEntries(id, []tracelog.TraceLogEntry{{Value: 1}})
+	s.Close()
+
+	s, err = openFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	tles, _ = s.Entries(id)
+	if len(tles) != 1 {
+		t.Errorf("Record after torn record lost, got %d entries", len(tles))
+	}
+}
+
+func TestFileStoreTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autorev.db")
+	s, err := openFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := s.AddTest(0, []byte{1}, "kbl")
+	s.Close()
+
+	// A complete but garbled last line is torn as well
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("{\"op\":\"ent\x00\n")
+	f.Close()
+
+	s, err = openFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.WriteEntries(id, []tracelog.TraceLogEntry{{Value: 1}})
+	s.Close()
+
+	s, err = openFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if tles, _ := s.Entries(id); len(tles) != 1 {
+		t.Errorf("Got %d entries, want 1", len(tles))
+	}
+}
+
+func TestFileStoreInvalidRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autorev.db")
+	os.WriteFile(path, []byte("garbage\n{\"op\":\"addtest\",\"test\":1}\n"), 0644)
+	if _, err := openFileStore(path); err == nil {
+		t.Errorf("Invalid record in the middle accepted")
+	}
+}
+
+func TestFileStoreLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autorev.db")
+	s, err := openFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openFileStore(path); err == nil {
+		t.Errorf("File opened twice")
+	}
+	s.Close()
+
+	s, err = openFileStore(path)
+	if err != nil {
+		t.Fatalf("Lock not released on close: %v", err)
+	}
+	s.Close()
+}
+
+func TestFileStoreConcurrentClaim(t *testing.T) {
+	s, err := openFileStore(filepath.Join(t.TempDir(), "autorev.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for i := 0; i < 20; i++ {
+		s.AddTest(0, []byte{byte(i)}, "kbl")
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	claimed := map[int]bool{}
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				id, err := s.ClaimNextTest(0, "dut")
+				if err != nil || id == 0 {
+					return
+				}
+				mu.Lock()
+				if claimed[id] {
+					t.Errorf("Test %d claimed twice", id)
+				}
+				claimed[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(claimed) != 20 {
+		t.Errorf("Claimed %d of 20 tests", len(claimed))
+	}
+}
+
+func TestFileStoreCampaigns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autorev.db")
+	s, err := openFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kbl, _ := s.AddCampaign(Campaign{Name: "kbl-sata", Snapshot: []byte("repeat: 2\n"), DefaultBlob: []byte{1}})
+	qemu, _ := s.AddCampaign(Campaign{Name: "qemu", DefaultBlob: []byte{2}})
+	if _, err := s.AddCampaign(Campaign{Name: "qemu"}); err == nil {
+		t.Errorf("Campaign name isn't unique")
+	}
+
+	a, _ := s.AddTest(kbl, []byte{1}, "kbl")
+	b, _ := s.AddTest(qemu, []byte{2}, "qemu")
+	s.Close()
+
+	s, err = openFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	c, _ := s.CampaignByName("kbl-sata")
+	if c.ID != kbl || string(c.Snapshot) != "repeat: 2\n" || c.DefaultBlob[0] != 1 {
+		t.Errorf("Wrong campaign %v", c)
+	}
+	if c, _ := s.CampaignByName("missing"); c.ID != 0 {
+		t.Errorf("Found missing campaign")
+	}
+
+	if id, _ := s.ClaimNextTest(qemu, "dut"); id != b {
+		t.Errorf("Claimed test %d of other campaign", id)
+	}
+	if id, _ := s.ClaimNextTest(qemu, "dut"); id != 0 {
+		t.Errorf("Claimed test %d of other campaign", id)
+	}
+	s.SetStatus(a, StatusSuccessful)
+	s.SetStatus(b, StatusSuccessful)
+	if ids, _ := s.SuccessfulTests(kbl); len(ids) != 1 || ids[0] != a {
+		t.Errorf("Wrong successful tests %v", ids)
+	}
+	if ids, _ := s.SuccessfulTests(0); len(ids) != 2 {
+		t.Errorf("Wrong successful tests of all campaigns %v", ids)
+	}
+	if configs, _ := s.Configs(qemu); len(configs) != 1 || configs[0][0] != 2 {
+		t.Errorf("Wrong configs %v", configs)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+// +build !windows
+
+package test
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile - Take an exclusive lock on f, fails if another process holds it
+// The lock is released when f is closed.
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
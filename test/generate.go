@@ -0,0 +1,298 @@
+package test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Strategies to generate tests from the variable options
+const (
+	// GenerateFull - Every combination of all option values
+	GenerateFull = "full"
+	// GeneratePairwise - Every combination of the values of any two options
+	GeneratePairwise = "pairwise"
+	// GenerateTWise - Every combination of the values of any Strength options
+	GenerateTWise = "twise"
+	// GenerateRandom - Samples combinations drawn at random
+	GenerateRandom = "random"
+	// GenerateAdaptive - Combinations of the options that change the trace, see adaptive.go
+	GenerateAdaptive = "adaptive"
+)
+
+// coveringCandidates - Tests built per row of a covering array, the one covering
+// the most new interactions is taken
+const coveringCandidates = 20
+
+// combinations - Returns all k element subsets of 0..n-1 in lexical order
+func combinations(n, k int) [][]int {
+	var ret [][]int
+	if k > n || k <= 0 {
+		return ret
+	}
+	c := make([]int, k)
+	for i := range c {
+		c[i] = i
+	}
+	for {
+		ret = append(ret, append([]int(nil), c...))
+		i := k - 1
+		for i >= 0 && c[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return ret
+		}
+		c[i]++
+		for j := i + 1; j < k; j++ {
+			c[j] = c[j-1] + 1
+		}
+	}
+}
+
+// productSize - Returns the number of combinations of the values of params, or
+// -1 if it exceeds limit
+func productSize(sizes []int, params []int, limit int) int {
+	n := 1
+	for _, p := range params {
+		n *= sizes[p]
+		if n > limit {
+			return -1
+		}
+	}
+	return n
+}
+
+// tupleIndex - Mixed radix index of the values test has for combo
+func tupleIndex(sizes []int, combo []int, test []int) int {
+	idx := 0
+	for _, p := range combo {
+		idx = idx*sizes[p] + test[p]
+	}
+	return idx
+}
+
+// fullProduct - Every combination, the first option changes slowest
+func fullProduct(sizes []int) [][]int {
+	tests := [][]int{make([]int, len(sizes))}
+	for p := range sizes {
+		var next [][]int
+		for _, t := range tests {
+			for v := 0; v < sizes[p]; v++ {
+				c := append([]int(nil), t...)
+				c[p] = v
+				next = append(next, c)
+			}
+		}
+		tests = next
+	}
+	return tests
+}
+
+// coveringArray - Greedily builds tests until every combination of the values of
+// any strength options is part of at least one test
+// Each test is seeded with an uncovered combination, the remaining options are
+// set one by one in random order to the value covering the most new
+// combinations.
+func coveringArray(sizes []int, strength int, rng *rand.Rand) [][]int {
+	n := len(sizes)
+	if strength >= n {
+		return fullProduct(sizes)
+	}
+
+	combos := combinations(n, strength)
+	uncovered := make([][]bool, len(combos))
+	// combos every option is part of
+	byParam := make([][]int, n)
+	remaining := 0
+	for i, c := range combos {
+		size := productSize(sizes, c, int(^uint(0)>>1))
+		uncovered[i] = make([]bool, size)
+		for j := range uncovered[i] {
+			uncovered[i][j] = true
+		}
+		remaining += size
+		for _, p := range c {
+			byParam[p] = append(byParam[p], i)
+		}
+	}
+
+	// gain - New combinations covered by setting p of test, all options set
+	// so far are >= 0
+	gain := func(test []int, p int) int {
+		g := 0
+		for _, i := range byParam[p] {
+			complete := true
+			for _, q := range combos[i] {
+				if test[q] < 0 {
+					complete = false
+					break
+				}
+			}
+			if complete && uncovered[i][tupleIndex(sizes, combos[i], test)] {
+				g++
+			}
+		}
+		return g
+	}
+
+	var tests [][]int
+	for remaining > 0 {
+		var best []int
+		bestGain := -1
+
+		for c := 0; c < coveringCandidates; c++ {
+			test := make([]int, n)
+			for p := range test {
+				test[p] = -1
+			}
+
+			// Seed with an uncovered combination of a random combo
+			start := rng.Intn(len(combos))
+			for k := range combos {
+				i := (start + k) % len(combos)
+				found := -1
+				for j, u := range uncovered[i] {
+					if u {
+						found = j
+						break
+					}
+				}
+				if found < 0 {
+					continue
+				}
+				for x := len(combos[i]) - 1; x >= 0; x-- {
+					p := combos[i][x]
+					test[p] = found % sizes[p]
+					found /= sizes[p]
+				}
+				break
+			}
+
+			for _, p := range rng.Perm(n) {
+				if test[p] >= 0 {
+					continue
+				}
+				bestV, bestVGain := 0, -1
+				for _, v := range rng.Perm(sizes[p]) {
+					test[p] = v
+					if g := gain(test, p); g > bestVGain {
+						bestV, bestVGain = v, g
+					}
+				}
+				test[p] = bestV
+			}
+
+			g := 0
+			for i, combo := range combos {
+				if uncovered[i][tupleIndex(sizes, combo, test)] {
+					g++
+				}
+			}
+			if g > bestGain {
+				best, bestGain = test, g
+			}
+		}
+
+		for i, combo := range combos {
+			idx := tupleIndex(sizes, combo, best)
+			if uncovered[i][idx] {
+				uncovered[i][idx] = false
+				remaining--
+			}
+		}
+		tests = append(tests, best)
+	}
+	return tests
+}
+
+// randomSample - Draws count distinct tests, all tests if there are less
+func randomSample(sizes []int, count int, rng *rand.Rand) [][]int {
+	all := make([]int, len(sizes))
+	for i := range all {
+		all[i] = i
+	}
+	if productSize(sizes, all, count) >= 0 {
+		return fullProduct(sizes)
+	}
+
+	var tests [][]int
+	seen := map[string]bool{}
+	for len(tests) < count {
+		test := make([]int, len(sizes))
+		for p := range test {
+			test[p] = rng.Intn(sizes[p])
+		}
+		key := fmt.Sprint(test)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		tests = append(tests, test)
+	}
+	return tests
+}
+
+// Coverage - Returns the number of combinations of the values of any strength
+// options that are part of at least one test, and the number of all of them
+func Coverage(sizes []int, tests [][]int, strength int) (int, int) {
+	covered, total := 0, 0
+	for _, combo := range combinations(len(sizes), strength) {
+		seen := map[int]bool{}
+		for _, t := range tests {
+			seen[tupleIndex(sizes, combo, t)] = true
+		}
+		covered += len(seen)
+		total += productSize(sizes, combo, int(^uint(0)>>1))
+	}
+	return covered, total
+}
+
+// GenerateTests - Returns the value indices of the tests for options with the
+// given number of values each, selected by strategy
+// strength is only used by GenerateTWise, samples only by GenerateRandom.
+// The same seed always returns the same tests.
+func GenerateTests(sizes []int, strategy string, strength int, samples int, seed int64) ([][]int, error) {
+	for i, size := range sizes {
+		if size <= 0 {
+			return nil, fmt.Errorf("Option %d has no values", i)
+		}
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	switch strategy {
+	case "", GenerateFull:
+		return fullProduct(sizes), nil
+	case GeneratePairwise:
+		return coveringArray(sizes, 2, rng), nil
+	case GenerateTWise:
+		if strength <= 0 {
+			strength = 3
+		}
+		return coveringArray(sizes, strength, rng), nil
+	case GenerateRandom:
+		if samples <= 0 {
+			return nil, fmt.Errorf("Random generation needs samples > 0")
+		}
+		return randomSample(sizes, samples, rng), nil
+	}
+	return nil, fmt.Errorf("Unknown generation strategy %q", strategy)
+}
+
+// CoverageReport - Formats the interaction coverage of tests for every strength
+// up to maxStrength, e.g. "1-wise 100.0% (6/6), 2-wise 83.3% (10/12)"
+func CoverageReport(sizes []int, tests [][]int, maxStrength int) string {
+	if maxStrength > len(sizes) {
+		maxStrength = len(sizes)
+	}
+	var parts []string
+	for t := 1; t <= maxStrength; t++ {
+		covered, total := Coverage(sizes, tests, t)
+		percent := 100.0
+		if total > 0 {
+			percent = 100 * float64(covered) / float64(total)
+		}
+		parts = append(parts, fmt.Sprintf("%d-wise %.1f%% (%d/%d)", t, percent, covered, total))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCombinations(t *testing.T) {
+	c := combinations(4, 2)
+	if fmt.Sprint(c) != "[[0 1] [0 2] [0 3] [1 2] [1 3] [2 3]]" {
+		t.Errorf("Wrong combinations %v", c)
+	}
+	if len(combinations(3, 4)) != 0 {
+		t.Errorf("Combinations of more elements than available")
+	}
+}
+
+func TestGenerateFull(t *testing.T) {
+	sizes := []int{2, 3}
+	tests, err := GenerateTests(sizes, GenerateFull, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first option changes slowest, like the former recursive generation
+	if fmt.Sprint(tests) != "[[0 0] [0 1] [0 2] [1 0] [1 1] [1 2]]" {
+		t.Errorf("Wrong tests %v", tests)
+	}
+	if covered, total := Coverage(sizes, tests, 2); covered != total || total != 6 {
+		t.Errorf("Full product covers %d of %d", covered, total)
+	}
+
+	tests, _ = GenerateTests(nil, GenerateFull, 0, 0, 0)
+	if len(tests) != 1 {
+		t.Errorf("No options must generate the default config only, got %d tests", len(tests))
+	}
+}
+
+func TestGenerateCoveringArray(t *testing.T) {
+	sizes := []int{3, 3, 3, 3, 2, 2, 2, 2}
+	full := 3 * 3 * 3 * 3 * 2 * 2 * 2 * 2
+
+	for _, strength := range []int{2, 3} {
+		strategy := GenerateTWise
+		if strength == 2 {
+			strategy = GeneratePairwise
+		}
+		tests, err := GenerateTests(sizes, strategy, strength, 0, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		covered, total := Coverage(sizes, tests, strength)
+		if covered != total {
+			t.Errorf("%d-wise covers %d of %d", strength, covered, total)
+		}
+		if len(tests) >= full/4 {
+			t.Errorf("%d-wise needs %d of %d tests", strength, len(tests), full)
+		}
+
+		again, _ := GenerateTests(sizes, strategy, strength, 0, 1)
+		if fmt.Sprint(again) != fmt.Sprint(tests) {
+			t.Errorf("%d-wise isn't reproducible with the same seed", strength)
+		}
+	}
+}
+
+func TestGenerateRandom(t *testing.T) {
+	sizes := []int{4, 4, 4}
+	tests, err := GenerateTests(sizes, GenerateRandom, 0, 10, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for _, test := range tests {
+		seen[fmt.Sprint(test)] = true
+	}
+	if len(tests) != 10 || len(seen) != 10 {
+		t.Errorf("Got %d tests, %d distinct", len(tests), len(seen))
+	}
+
+	tests, _ = GenerateTests(sizes, GenerateRandom, 0, 100, 7)
+	if len(tests) != 64 {
+		t.Errorf("More samples than configs must return all %d, got %d", 64, len(tests))
+	}
+
+	if _, err := GenerateTests(sizes, GenerateRandom, 0, 0, 7); err == nil {
+		t.Errorf("Random generation without samples succeeded")
+	}
+	if _, err := GenerateTests(sizes, "bogus", 0, 0, 7); err == nil {
+		t.Errorf("Unknown strategy succeeded")
+	}
+}
+
+func TestGenerateInvalidOptions(t *testing.T) {
+	if _, err := GenerateTests([]int{2, 0, 2}, GeneratePairwise, 0, 0, 1); err == nil {
+		t.Errorf("Option without values succeeded")
+	}
+	if report := CoverageReport([]int{2, 0}, nil, 2); strings.Contains(report, "NaN") {
+		t.Errorf("Coverage without combinations is %s", report)
+	}
+
+	cfg := adaptiveConfig()
+	blob := make([]byte, 4)
+	if _, err := (&test{}).GenNewTestsFromCfg(cfg, blob, true); err != nil {
+		t.Fatal(err)
+	}
+	cfg.TraceLog.VariableFirmareOptions[1].Min = 2
+	if _, err := (&test{}).GenNewTestsFromCfg(cfg, blob, true); err == nil {
+		t.Errorf("Option with Max below Min succeeded")
+	}
+	cfg = adaptiveConfig()
+	if _, err := (&test{}).GenNewTestsFromCfg(cfg, blob[:3], true); err == nil {
+		t.Errorf("Option outside of the blob succeeded")
+	}
+}
@@ -0,0 +1,222 @@
+package test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// migration - Forward step of the MySQL schema
+type migration struct {
+	version     int
+	description string
+	statements  []string
+}
+
+// migrations - Applied in order, never change a released migration, append a new one
+// Statements creating something that exists already are skipped, so databases
+// set up from the old SQL dump or an interrupted migration can be upgraded.
+var migrations = []migration{
+	{1, "Initial schema", []string{
+		"CREATE TABLE `updDefaults` (" +
+			"`updId` int(10) unsigned NOT NULL AUTO_INCREMENT, " +
+			"`platformName` text NOT NULL, " +
+			"`size` int(10) unsigned NOT NULL, " +
+			"`configBlob` blob NOT NULL, " +
+			"PRIMARY KEY (`updId`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+		"CREATE TABLE `tests` (" +
+			"`idTests` int(11) NOT NULL AUTO_INCREMENT, " +
+			"`status` tinyint(4) DEFAULT NULL, " +
+			"`ts_added` timestamp NULL DEFAULT NULL, " +
+			"`ts_started` timestamp NULL DEFAULT NULL, " +
+			"`ts_finished` timestamp NULL DEFAULT NULL, " +
+			"`completeLog` blob, " +
+			"`config` blob, " +
+			"`fk_defaultConfig` int(10) unsigned NOT NULL, " +
+			"PRIMARY KEY (`idTests`), " +
+			"KEY `fk_defaultConfig` (`fk_defaultConfig`), " +
+			"CONSTRAINT `fk_defaultConfig` FOREIGN KEY (`fk_defaultConfig`) REFERENCES `updDefaults` (`updId`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+		"CREATE TABLE `traceLog` (" +
+			"`idTraceLog` int(11) NOT NULL AUTO_INCREMENT, " +
+			"`type` int(2) DEFAULT NULL, " +
+			"`input` tinyint(4) DEFAULT NULL, " +
+			"`address` bigint(20) DEFAULT NULL, " +
+			"`value` bigint(20) unsigned NOT NULL, " +
+			"`ip` bigint(20) DEFAULT NULL, " +
+			"`accessSize` int(3) unsigned DEFAULT '0', " +
+			"`fk_idTests` int(11) NOT NULL, " +
+			"PRIMARY KEY (`idTraceLog`), " +
+			"KEY `fk_idtests_id` (`fk_idTests`), " +
+			"CONSTRAINT `fk_idtests` FOREIGN KEY (`fk_idTests`) REFERENCES `tests` (`idTests`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	}},
+	{2, "Complete console log and capture windows", []string{
+		"ALTER TABLE `tests` MODIFY `completeLog` longblob",
+		"ALTER TABLE `traceLog` ADD COLUMN `captureWindow` varchar(64) NOT NULL DEFAULT '' AFTER `accessSize`",
+	}},
+	{3, "BL_FAKE rules and BL_FILTER filters", []string{
+		"CREATE TABLE `fakeRules` (" +
+			"`idFakeRule` int(11) NOT NULL AUTO_INCREMENT, " +
+			"`type` int(2) DEFAULT NULL, " +
+			"`input` tinyint(4) DEFAULT NULL, " +
+			"`address` bigint(20) unsigned DEFAULT NULL, " +
+			"`value` bigint(20) unsigned NOT NULL, " +
+			"`ip` bigint(20) unsigned DEFAULT NULL, " +
+			"`fk_idTests` int(11) NOT NULL, " +
+			"PRIMARY KEY (`idFakeRule`), " +
+			"KEY `fk_fakerules_idtests_id` (`fk_idTests`), " +
+			"CONSTRAINT `fk_fakerules_idtests` FOREIGN KEY (`fk_idTests`) REFERENCES `tests` (`idTests`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+		"CREATE TABLE `filters` (" +
+			"`idFilter` int(11) NOT NULL AUTO_INCREMENT, " +
+			"`type` int(2) DEFAULT NULL, " +
+			"`address` bigint(20) unsigned DEFAULT NULL, " +
+			"`fk_idTests` int(11) NOT NULL, " +
+			"PRIMARY KEY (`idFilter`), " +
+			"KEY `fk_filters_idtests_id` (`fk_idTests`), " +
+			"CONSTRAINT `fk_filters_idtests` FOREIGN KEY (`fk_idTests`) REFERENCES `tests` (`idTests`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	}},
+	{4, "Attempts, failure reasons and DUT control commands", []string{
+		"ALTER TABLE `tests` ADD COLUMN `failureReason` varchar(64) NOT NULL DEFAULT '' AFTER `completeLog`",
+		"CREATE TABLE `attempts` (" +
+			"`idAttempt` int(11) NOT NULL AUTO_INCREMENT, " +
+			"`number` int(11) NOT NULL, " +
+			"`ts_started` timestamp NULL DEFAULT NULL, " +
+			"`ts_finished` timestamp NULL DEFAULT NULL, " +
+			"`failureReason` varchar(64) NOT NULL DEFAULT '', " +
+			"`entries` int(11) NOT NULL DEFAULT '0', " +
+			"`error` text, " +
+			"`fk_idTests` int(11) NOT NULL, " +
+			"PRIMARY KEY (`idAttempt`), " +
+			"KEY `fk_attempts_idtests_id` (`fk_idTests`), " +
+			"CONSTRAINT `fk_attempts_idtests` FOREIGN KEY (`fk_idTests`) REFERENCES `tests` (`idTests`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+		"CREATE TABLE `commands` (" +
+			"`idCommand` int(11) NOT NULL AUTO_INCREMENT, " +
+			"`name` varchar(16) NOT NULL, " +
+			"`command` text, " +
+			"`ts_started` timestamp NULL DEFAULT NULL, " +
+			"`duration` int(11) unsigned DEFAULT '0', " +
+			"`exitCode` int(11) DEFAULT NULL, " +
+			"`stdout` mediumblob, " +
+			"`stderr` mediumblob, " +
+			"`error` text, " +
+			"`fk_idTests` int(11) NOT NULL, " +
+			"PRIMARY KEY (`idCommand`), " +
+			"KEY `fk_commands_idtests_id` (`fk_idTests`), " +
+			"CONSTRAINT `fk_commands_idtests` FOREIGN KEY (`fk_idTests`) REFERENCES `tests` (`idTests`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	}},
+	{5, "CPUID registers, string IO and unsigned addresses", []string{
+		"ALTER TABLE `traceLog` MODIFY `address` bigint(20) unsigned DEFAULT NULL, MODIFY `ip` bigint(20) unsigned DEFAULT NULL",
+		"ALTER TABLE `traceLog` ADD COLUMN `subleaf` int(10) unsigned NOT NULL DEFAULT '0' AFTER `captureWindow`",
+		"ALTER TABLE `traceLog` ADD COLUMN `eax` int(10) unsigned NOT NULL DEFAULT '0' AFTER `subleaf`",
+		"ALTER TABLE `traceLog` ADD COLUMN `ebx` int(10) unsigned NOT NULL DEFAULT '0' AFTER `eax`",
+		"ALTER TABLE `traceLog` ADD COLUMN `ecx` int(10) unsigned NOT NULL DEFAULT '0' AFTER `ebx`",
+		"ALTER TABLE `traceLog` ADD COLUMN `edx` int(10) unsigned NOT NULL DEFAULT '0' AFTER `ecx`",
+		"ALTER TABLE `traceLog` ADD COLUMN `count` int(10) unsigned NOT NULL DEFAULT '0' AFTER `edx`",
+		"ALTER TABLE `fakeRules` MODIFY `address` bigint(20) unsigned DEFAULT NULL, MODIFY `ip` bigint(20) unsigned DEFAULT NULL",
+		"ALTER TABLE `filters` MODIFY `address` bigint(20) unsigned DEFAULT NULL",
+	}},
+	{6, "Host receive time and DUT time stamp counter", []string{
+		"ALTER TABLE `traceLog` ADD COLUMN `tsc` bigint(20) unsigned NOT NULL DEFAULT '0' AFTER `count`",
+		"ALTER TABLE `traceLog` ADD COLUMN `received` datetime(6) DEFAULT NULL AFTER `tsc`",
+	}},
+	{7, "DUT profiles", []string{
+		"ALTER TABLE `tests` ADD COLUMN `dut` varchar(64) NOT NULL DEFAULT '' AFTER `failureReason`",
+	}},
+	{8, "Campaigns", []string{
+		"CREATE TABLE `campaigns` (" +
+			"`idCampaign` int(11) NOT NULL AUTO_INCREMENT, " +
+			"`name` varchar(64) NOT NULL, " +
+			"`ts_created` timestamp NULL DEFAULT NULL, " +
+			"`snapshot` mediumblob, " +
+			"`defaultBlob` blob, " +
+			"PRIMARY KEY (`idCampaign`), " +
+			"UNIQUE KEY `campaign_name` (`name`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+		"ALTER TABLE `tests` ADD COLUMN `fk_campaign` int(11) DEFAULT NULL AFTER `fk_defaultConfig`",
+		"ALTER TABLE `tests` ADD CONSTRAINT `fk_campaign` FOREIGN KEY (`fk_campaign`) REFERENCES `campaigns` (`idCampaign`)",
+	}},
+}
+
+// SchemaVersion - Version of the schema this build expects
+var SchemaVersion = migrations[len(migrations)-1].version
+
+// alreadyApplied - Returns true if the statement failed as its table, column,
+// key or constraint exists already
+func alreadyApplied(err error) bool {
+	var me *mysql.MySQLError
+	if !errors.As(err, &me) {
+		return false
+	}
+	switch me.Number {
+	case 1050, // ER_TABLE_EXISTS_ERROR
+		1060, // ER_DUP_FIELDNAME
+		1061, // ER_DUP_KEYNAME
+		1826: // ER_FK_DUP_NAME
+		return true
+	}
+	return false
+}
+
+// schemaVersion - Returns the version of the database, 0 if no migration has been applied
+func (s *mysqlStore) schemaVersion() (int, error) {
+	var version sql.NullInt64
+	err := s.db.QueryRow("SELECT MAX(version) FROM schemaVersion").Scan(&version)
+	if err != nil {
+		var me *mysql.MySQLError
+		if errors.As(err, &me) && me.Number == 1146 { // ER_NO_SUCH_TABLE
+			return 0, nil
+		}
+		return -1, err
+	}
+	return int(version.Int64), nil
+}
+
+// Migrate - Creates the schema or applies the missing migrations
+func (s *mysqlStore) Migrate() error {
+	_, err := s.db.Exec("CREATE TABLE IF NOT EXISTS `schemaVersion` (" +
+		"`version` int(11) NOT NULL, " +
+		"`description` varchar(255) NOT NULL DEFAULT '', " +
+		"`ts_applied` timestamp NULL DEFAULT NULL, " +
+		"PRIMARY KEY (`version`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4")
+	if err != nil {
+		return err
+	}
+
+	current, err := s.schemaVersion()
+	if err != nil {
+		return err
+	}
+	if current > SchemaVersion {
+		return fmt.Errorf("Database schema version %d is newer than %d, update autorev", current, SchemaVersion)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		log.Printf("Applying migration %d: %s\n", m.version, m.description)
+		for _, stmt := range m.statements {
+			_, err = s.db.Exec(stmt)
+			if err != nil && !alreadyApplied(err) {
+				return fmt.Errorf("Migration %d failed: %v", m.version, err)
+			}
+		}
+		_, err = s.db.Exec("INSERT INTO schemaVersion (version, description, ts_applied) VALUES (?, ?, NOW())", m.version, m.description)
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("Database schema is at version %d\n", SchemaVersion)
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestMigrationsOrdered(t *testing.T) {
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("Migration %d has version %d", i+1, m.version)
+		}
+		if len(m.description) == 0 || len(m.statements) == 0 {
+			t.Errorf("Migration %d is incomplete", m.version)
+		}
+		for _, stmt := range m.statements {
+			if strings.Contains(stmt, "IF NOT EXISTS") || strings.HasPrefix(stmt, "DROP") {
+				t.Errorf("Migration %d must rely on alreadyApplied instead: %s", m.version, stmt)
+			}
+		}
+	}
+	if SchemaVersion != len(migrations) {
+		t.Errorf("SchemaVersion %d doesn't match the last migration", SchemaVersion)
+	}
+}
+
+// fakeSchema - Tracks the tables, columns and constraints created by the
+// migrations and fails like MySQL if they exist already
+type fakeSchema struct {
+	mu       sync.Mutex
+	objects  map[string]bool
+	versions []int64
+	executed []string
+}
+
+var (
+	createTable   = regexp.MustCompile("^CREATE TABLE `(\\w+)`")
+	addColumn     = regexp.MustCompile("^ALTER TABLE `(\\w+)` ADD COLUMN `(\\w+)`")
+	addConstraint = regexp.MustCompile("^ALTER TABLE `\\w+` ADD CONSTRAINT `(\\w+)`")
+)
+
+func (f *fakeSchema) exec(query string, args []driver.Value) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS `schemaVersion`"):
+		f.objects["table schemaVersion"] = true
+		return nil
+	case strings.HasPrefix(query, "INSERT INTO schemaVersion"):
+		f.versions = append(f.versions, args[0].(int64))
+		return nil
+	}
+
+	f.executed = append(f.executed, query)
+	var key string
+	var number uint16
+	if m := createTable.FindStringSubmatch(query); m != nil {
+		key, number = "table "+m[1], 1050
+	} else if m := addColumn.FindStringSubmatch(query); m != nil {
+		key, number = "column "+m[1]+"."+m[2], 1060
+	} else if m := addConstraint.FindStringSubmatch(query); m != nil {
+		key, number = "constraint "+m[1], 1826
+	} else {
+		return nil
+	}
+	if f.objects[key] {
+		return &mysql.MySQLError{Number: number, Message: key + " exists"}
+	}
+	f.objects[key] = true
+	return nil
+}
+
+func (f *fakeSchema) version() (driver.Value, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.objects["table schemaVersion"] {
+		return nil, &mysql.MySQLError{Number: 1146, Message: "no such table"}
+	}
+	var max driver.Value
+	for _, v := range f.versions {
+		if max == nil || v > max.(int64) {
+			max = v
+		}
+	}
+	return max, nil
+}
+
+var (
+	fakeSchemasMu sync.Mutex
+	fakeSchemas   = map[string]*fakeSchema{}
+)
+
+type fakeSchemaDriver struct{}
+
+func (fakeSchemaDriver) Open(name string) (driver.Conn, error) {
+	fakeSchemasMu.Lock()
+	defer fakeSchemasMu.Unlock()
+	return fakeSchemaConn{fakeSchemas[name]}, nil
+}
+
+type fakeSchemaConn struct{ f *fakeSchema }
+
+func (c fakeSchemaConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeSchemaStmt{c.f, query}, nil
+}
+func (c fakeSchemaConn) Close() error              { return nil }
+func (c fakeSchemaConn) Begin() (driver.Tx, error) { return nil, fmt.Errorf("No transactions") }
+
+type fakeSchemaStmt struct {
+	f     *fakeSchema
+	query string
+}
+
+func (s fakeSchemaStmt) Close() error  { return nil }
+func (s fakeSchemaStmt) NumInput() int { return -1 }
+func (s fakeSchemaStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), s.f.exec(s.query, args)
+}
+func (s fakeSchemaStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.query != "SELECT MAX(version) FROM schemaVersion" {
+		return nil, fmt.Errorf("Unexpected query %s", s.query)
+	}
+	v, err := s.f.version()
+	if err != nil {
+		return nil, err
+	}
+	return &fakeSchemaRows{value: v}, nil
+}
+
+type fakeSchemaRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *fakeSchemaRows) Columns() []string { return []string{"version"} }
+func (r *fakeSchemaRows) Close() error      { return nil }
+func (r *fakeSchemaRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func init() {
+	sql.Register("fakeschema", fakeSchemaDriver{})
+}
+
+// openFakeSchema - mysqlStore on a fake schema with the given objects
+func openFakeSchema(t *testing.T, objects ...string) (*mysqlStore, *fakeSchema) {
+	f := &fakeSchema{objects: map[string]bool{}}
+	for _, o := range objects {
+		f.objects[o] = true
+	}
+	fakeSchemasMu.Lock()
+	fakeSchemas[t.Name()] = f
+	fakeSchemasMu.Unlock()
+
+	db, err := sql.Open("fakeschema", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &mysqlStore{db: db}, f
+}
+
+func TestMigrate(t *testing.T) {
+	s, f := openFakeSchema(t)
+	if v, err := s.schemaVersion(); err != nil || v != 0 {
+		t.Fatalf("Empty database has version %d, %v", v, err)
+	}
+
+	if err := s.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.schemaVersion(); v != SchemaVersion {
+		t.Errorf("Migrated to version %d, want %d", v, SchemaVersion)
+	}
+	if len(f.versions) != len(migrations) {
+		t.Errorf("Recorded %d migrations, want %d", len(f.versions), len(migrations))
+	}
+	statements := 0
+	for _, m := range migrations {
+		statements += len(m.statements)
+	}
+	if len(f.executed) != statements {
+		t.Errorf("Executed %d statements, want %d", len(f.executed), statements)
+	}
+
+	// A second -initdb must not touch the schema
+	executed := len(f.executed)
+	if err := s.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.executed) != executed || len(f.versions) != len(migrations) {
+		t.Errorf("Second migration executed %d statements", len(f.executed)-executed)
+	}
+}
+
+func TestMigrateExistingSchema(t *testing.T) {
+	// Set up from the old SQL dump or interrupted in the middle of migration 8
+	s, f := openFakeSchema(t, "table updDefaults", "table tests", "table traceLog",
+		"column traceLog.captureWindow", "table campaigns", "constraint fk_campaign")
+	if err := s.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.schemaVersion(); v != SchemaVersion {
+		t.Errorf("Migrated to version %d, want %d", v, SchemaVersion)
+	}
+	if !f.objects["column tests.fk_campaign"] || !f.objects["table fakeRules"] {
+		t.Errorf("Missing objects not created: %v", f.objects)
+	}
+}
+
+func TestMigrateNewerSchema(t *testing.T) {
+	s, f := openFakeSchema(t, "table schemaVersion")
+	f.versions = []int64{int64(SchemaVersion + 1)}
+	if err := s.Migrate(); err == nil {
+		t.Errorf("Newer schema accepted")
+	}
+}
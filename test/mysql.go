@@ -0,0 +1,524 @@
+package test
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/9elements/autorev/config"
+	"github.com/9elements/autorev/tracelog"
+)
+
+// mysqlStore - Store backed by a MySQL server, see migrate.go for the schema
+type mysqlStore struct {
+	db *sql.DB
+}
+
+// mysqlDSN - Data source name of the autorev database, a unix socket is used
+// if no hostname is given
+func mysqlDSN(cfg config.Config) string {
+	addr := ""
+	if len(cfg.Database.HostName) > 0 {
+		port := cfg.Database.Port
+		if port == 0 {
+			port = 3306
+		}
+		addr = fmt.Sprintf("tcp(%s:%d)", cfg.Database.HostName, port)
+	}
+	return fmt.Sprintf("%s:%s@%s/autorev?parseTime=true", cfg.Database.Username, cfg.Database.Password, addr)
+}
+
+func openMysqlStore(cfg config.Config) (*mysqlStore, error) {
+	log.Println("Seting up database connection..")
+	db, err := sql.Open("mysql", mysqlDSN(cfg))
+	if err != nil {
+		return nil, err
+	}
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	s := &mysqlStore{db: db}
+
+	version, err := s.schemaVersion()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if version < SchemaVersion {
+		log.Printf("Database schema version %d is outdated, run -initdb to migrate to %d\n", version, SchemaVersion)
+	}
+	return s, nil
+}
+
+func (s *mysqlStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *mysqlStore) NextTest(campaign int) (int, error) {
+	var id int
+	err := s.db.QueryRow("SELECT idTests FROM tests WHERE status = 0 AND (? = 0 OR fk_campaign = ?) ORDER BY ts_added ASC LIMIT 1", campaign, campaign).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+func (s *mysqlStore) ClaimNextTest(campaign int, dut string) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return -1, err
+	}
+
+	// Rows locked by other workers are skipped instead of waiting for them
+	var id int
+	err = tx.QueryRow("SELECT idTests FROM tests WHERE status = 0 AND (? = 0 OR fk_campaign = ?) ORDER BY ts_added ASC LIMIT 1 FOR UPDATE SKIP LOCKED", campaign, campaign).Scan(&id)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return 0, nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+
+	_, err = tx.Exec("UPDATE tests SET status = 1, ts_started = NOW(), dut = ? WHERE idTests = ?", dut, id)
+	if err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+func (s *mysqlStore) SetStatus(testID int, status Status) error {
+	query := "UPDATE tests SET status = ?, ts_finished = NOW() WHERE idTests = ?"
+	if status == StatusRunning {
+		query = "UPDATE tests SET status = ?, ts_started = NOW() WHERE idTests = ?"
+	}
+	_, err := s.db.Exec(query, int(status), testID)
+	return err
+}
+
+func (s *mysqlStore) AddTest(campaign int, configBlob []byte, defaultConfig string) (int, error) {
+	stmt, err := s.db.Prepare("INSERT INTO tests (status, ts_added, config, fk_defaultConfig, fk_campaign) VALUES (0, NOW(), ?, (SELECT updId FROM updDefaults WHERE platformName = ?), ?)")
+	if err != nil {
+		return -1, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(configBlob, defaultConfig, sql.NullInt64{Int64: int64(campaign), Valid: campaign > 0})
+	if err != nil {
+		return -1, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+	return int(id), nil
+}
+
+func (s *mysqlStore) Config(testID int) ([]byte, error) {
+	var config []byte
+	err := s.db.QueryRow("SELECT config FROM tests WHERE idTests = ? LIMIT 1", testID).Scan(&config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (s *mysqlStore) SuccessfulTests(campaign int) ([]int, error) {
+	var ids []int
+
+	rows, err := s.db.Query("SELECT idTests FROM tests WHERE status = 2 AND (? = 0 OR fk_campaign = ?) ORDER BY idTests ASC", campaign, campaign)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *mysqlStore) Configs(campaign int) ([][]byte, error) {
+	var configs [][]byte
+
+	rows, err := s.db.Query("SELECT config FROM tests WHERE (? = 0 OR fk_campaign = ?) ORDER BY idTests ASC", campaign, campaign)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var config []byte
+		err = rows.Scan(&config)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	return configs, rows.Err()
+}
+
+func (s *mysqlStore) AddCampaign(c Campaign) (int, error) {
+	res, err := s.db.Exec("INSERT INTO campaigns (name, ts_created, snapshot, defaultBlob) VALUES (?, NOW(), ?, ?)", c.Name, c.Snapshot, c.DefaultBlob)
+	if err != nil {
+		return -1, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+	return int(id), nil
+}
+
+func (s *mysqlStore) CampaignByName(name string) (Campaign, error) {
+	var c Campaign
+	var created sql.NullTime
+	err := s.db.QueryRow("SELECT idCampaign, name, ts_created, snapshot, defaultBlob FROM campaigns WHERE name = ?", name).Scan(&c.ID, &c.Name, &created, &c.Snapshot, &c.DefaultBlob)
+	if err == sql.ErrNoRows {
+		return Campaign{}, nil
+	}
+	c.Created = created.Time
+	return c, err
+}
+
+func (s *mysqlStore) Campaigns() ([]Campaign, error) {
+	var campaigns []Campaign
+
+	rows, err := s.db.Query("SELECT idCampaign, name, ts_created FROM campaigns ORDER BY idCampaign ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c Campaign
+		var created sql.NullTime
+		err = rows.Scan(&c.ID, &c.Name, &created)
+		if err != nil {
+			return nil, err
+		}
+		c.Created = created.Time
+		campaigns = append(campaigns, c)
+	}
+	return campaigns, rows.Err()
+}
+
+func (s *mysqlStore) AddDefaultConfig(name string, blob []byte) error {
+	// TODO: Check if platform already exists - ask to overwrite, cancel or new name
+	stmt, err := s.db.Prepare("INSERT INTO `updDefaults` (`platformName`, `size`, `configBlob`) VALUES (?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(name, len(blob), blob)
+	return err
+}
+
+func (s *mysqlStore) DefaultConfig(name string) ([]byte, error) {
+	var config []byte
+	err := s.db.QueryRow("SELECT configBlob FROM updDefaults WHERE platformName = ? LIMIT 1", name).Scan(&config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (s *mysqlStore) SetCompleteLog(testID int, completeLog []byte) error {
+	stmtUpdate, err := s.db.Prepare("UPDATE tests SET completeLog = ? WHERE idTests = ?")
+	if err != nil {
+		return err
+	}
+	defer stmtUpdate.Close()
+
+	_, err = stmtUpdate.Exec(completeLog, testID)
+	return err
+}
+
+func (s *mysqlStore) CompleteLog(testID int) ([]byte, error) {
+	var completeLog []byte
+	err := s.db.QueryRow("SELECT completeLog FROM tests WHERE idTests = ?", testID).Scan(&completeLog)
+	if err != nil {
+		return nil, err
+	}
+	return completeLog, nil
+}
+
+// SetAttempts - The failure reason of the last attempt is stored with the test
+func (s *mysqlStore) SetAttempts(testID int, attempts []tracelog.Attempt) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM attempts WHERE fk_idTests = ?", testID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO attempts (number, ts_started, ts_finished, failureReason, entries, error, fk_idTests) VALUES (?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, a := range attempts {
+		_, err = stmt.Exec(a.Number, a.Started, a.Finished, string(a.Reason), a.Entries, a.Error, testID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	reason := ""
+	if len(attempts) > 0 {
+		reason = string(attempts[len(attempts)-1].Reason)
+	}
+	_, err = tx.Exec("UPDATE tests SET failureReason = ? WHERE idTests = ?", reason, testID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *mysqlStore) Attempts(testID int) ([]tracelog.Attempt, error) {
+	var attempts []tracelog.Attempt
+
+	rows, err := s.db.Query("SELECT number, ts_started, ts_finished, failureReason, entries, error FROM attempts WHERE fk_idTests = ? ORDER BY number ASC", testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a tracelog.Attempt
+		var reason string
+		err = rows.Scan(&a.Number, &a.Started, &a.Finished, &reason, &a.Entries, &a.Error)
+		if err != nil {
+			return nil, err
+		}
+		a.Reason = tracelog.FailureReason(reason)
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+func (s *mysqlStore) AddCommands(testID int, commands []tracelog.CommandResult) error {
+	stmt, err := s.db.Prepare("INSERT INTO commands (name, command, ts_started, duration, exitCode, stdout, stderr, error, fk_idTests) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range commands {
+		_, err = stmt.Exec(c.Name, c.Command, c.Started, c.Duration.Milliseconds(), c.ExitCode, c.Stdout, c.Stderr, c.Error, testID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *mysqlStore) WriteEntries(testID int, entries []tracelog.TraceLogEntry) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO traceLog (type, input, address, value, ip, accessSize, captureWindow, subleaf, eax, ebx, ecx, edx, count, tsc, received, fk_idTests) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		_, err = stmt.Exec(entry.Type, entry.Inout, entry.Address, entry.Value, entry.IP, entry.AccessSize, entry.Window,
+			entry.Subleaf, entry.Regs[0], entry.Regs[1], entry.Regs[2], entry.Regs[3], entry.Count,
+			entry.TSC, sql.NullTime{Time: entry.Received, Valid: !entry.Received.IsZero()}, testID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *mysqlStore) ResetEntries(testID int) error {
+	_, err := s.db.Exec("DELETE FROM traceLog WHERE fk_idTests = ?", testID)
+	return err
+}
+
+func (s *mysqlStore) Entries(testID int) ([]tracelog.TraceLogEntry, error) {
+	var traceLogEntries []tracelog.TraceLogEntry
+
+	rows, err := s.db.Query("SELECT type, input, address, value, ip, accessSize, subleaf, eax, ebx, ecx, edx, count, tsc, captureWindow, received FROM traceLog WHERE fk_idTests = ? ORDER BY idTraceLog ASC", testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]uint64, 13)
+	scanArgs := make([]interface{}, len(values)+1)
+
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	var window string
+	var received sql.NullTime
+	scanArgs[len(values)] = &window
+	scanArgs = append(scanArgs, &received)
+
+	for rows.Next() {
+		err = rows.Scan(scanArgs...)
+		if err != nil {
+			return nil, err
+		}
+
+		tracelogentry := tracelog.TraceLogEntry{
+			Type:       int(values[0]),
+			Inout:      (values[1] != 0),
+			Address:    uint(values[2]),
+			Value:      uint64(values[3]),
+			IP:         uint(values[4]),
+			AccessSize: uint(values[5]),
+			Subleaf:    uint(values[6]),
+			Regs:       [4]uint32{uint32(values[7]), uint32(values[8]), uint32(values[9]), uint32(values[10])},
+			Count:      uint(values[11]),
+			TSC:        values[12],
+			Received:   received.Time,
+			Window:     window,
+		}
+		traceLogEntries = append(traceLogEntries, tracelogentry)
+	}
+	return traceLogEntries, rows.Err()
+}
+
+func (s *mysqlStore) AddFakeRules(testID int, rules []tracelog.FakeRule) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO fakeRules (type, input, address, value, ip, fk_idTests) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, rule := range rules {
+		_, err = stmt.Exec(rule.Type, rule.Inout, rule.Address, rule.Value, rule.IP, testID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *mysqlStore) FakeRules(testID int) ([]tracelog.FakeRule, error) {
+	var rules []tracelog.FakeRule
+
+	rows, err := s.db.Query("SELECT type, input, address, value, ip FROM fakeRules WHERE fk_idTests = ? ORDER BY idFakeRule ASC", testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]uint64, 5)
+	scanArgs := make([]interface{}, len(values))
+
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		err = rows.Scan(scanArgs...)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, tracelog.FakeRule{
+			Type:    int(values[0]),
+			Inout:   (values[1] != 0),
+			Address: uint(values[2]),
+			Value:   uint64(values[3]),
+			IP:      uint(values[4]),
+		})
+	}
+	return rules, rows.Err()
+}
+
+func (s *mysqlStore) SetFilters(testID int, filters []tracelog.Filter) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM filters WHERE fk_idTests = ?", testID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO filters (type, address, fk_idTests) VALUES (?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, filter := range filters {
+		_, err = stmt.Exec(filter.Type, filter.Address, testID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *mysqlStore) Filters(testID int) ([]tracelog.Filter, error) {
+	var filters []tracelog.Filter
+
+	rows, err := s.db.Query("SELECT type, address FROM filters WHERE fk_idTests = ? ORDER BY idFilter ASC", testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var filterType int
+		var address uint64
+		err = rows.Scan(&filterType, &address)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, tracelog.Filter{Type: filterType, Address: uint(address)})
+	}
+	return filters, rows.Err()
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/9elements/autorev/tracelog"
+)
+
+// openTestMysqlStore - Connects to the throwaway database given by
+// AUTOREV_TEST_MYSQL, e.g. "root:secret@tcp(127.0.0.1:3306)/autorev_test?parseTime=true".
+// All its tables are dropped.
+func openTestMysqlStore(t *testing.T) *mysqlStore {
+	dsn := os.Getenv("AUTOREV_TEST_MYSQL")
+	if len(dsn) == 0 {
+		t.Skip("AUTOREV_TEST_MYSQL not set")
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	db.Exec("SET FOREIGN_KEY_CHECKS = 0")
+	for _, table := range []string{"schemaVersion", "campaigns", "commands", "attempts", "filters", "fakeRules", "traceLog", "tests", "updDefaults"} {
+		if _, err := db.Exec("DROP TABLE IF EXISTS `" + table + "`"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Exec("SET FOREIGN_KEY_CHECKS = 1")
+	return &mysqlStore{db: db}
+}
+
+func TestMysqlStoreEntries(t *testing.T) {
+	s := openTestMysqlStore(t)
+	if err := s.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.AddDefaultConfig("kbl", []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	id, err := s.AddTest(0, []byte{1}, "kbl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.WriteEntries(id, []tracelog.TraceLogEntry{
+		{Type: int(tracelog.IO), Address: 0x80, Value: 0xddaa, Window: "romstage"},
+		{Type: int(tracelog.CPUID), Address: 7, Subleaf: 0, Regs: [4]uint32{1, 2, 3, 4}, Window: "ramstage"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tles, err := s.Entries(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tles) != 2 || tles[0].Window != "romstage" || tles[1].Regs[3] != 4 || tles[1].Window != "ramstage" {
+		t.Errorf("Wrong entries %v", tles)
+	}
+}
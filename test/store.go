@@ -0,0 +1,109 @@
+package test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/9elements/autorev/config"
+	"github.com/9elements/autorev/tracelog"
+)
+
+// Status - State of a test, stored in the status column of the tests table
+type Status int
+
+const (
+	// StatusNew - Test hasn't run yet
+	StatusNew Status = 0
+	// StatusRunning - Test is being collected
+	StatusRunning Status = 1
+	// StatusSuccessful - Trace has been collected
+	StatusSuccessful Status = 2
+	// StatusFailed - All attempts failed
+	StatusFailed Status = 3
+)
+
+// Campaign - Named experiment, tests of different campaigns are never mixed
+type Campaign struct {
+	ID      int
+	Name    string
+	Created time.Time
+	// Trace log settings the campaign has been created with, see config.CampaignSnapshot
+	Snapshot []byte
+	// Default config blob the tests are derived from
+	DefaultBlob []byte
+}
+
+// Store - Persists tests, default configs and trace entries
+// All methods must be safe for concurrent use by multiple workers.
+type Store interface {
+	// NextTest - Returns the oldest new test of the campaign, 0 if there is none
+	// A campaign of 0 selects tests of all campaigns, here and below.
+	NextTest(campaign int) (int, error)
+	// ClaimNextTest - Atomically sets the oldest new test running on dut, 0 if there is none
+	ClaimNextTest(campaign int, dut string) (int, error)
+	// SetStatus - Update the status of a test and its start or finish time
+	SetStatus(testID int, status Status) error
+	// AddTest - Insert a new test with the config blob, based on the named default config
+	AddTest(campaign int, configBlob []byte, defaultConfig string) (int, error)
+	// Config - Returns the config blob of a test
+	Config(testID int) ([]byte, error)
+	// SuccessfulTests - Returns the IDs of all successful tests in ascending order
+	SuccessfulTests(campaign int) ([]int, error)
+	// Configs - Returns the config blobs of all tests regardless of their status
+	Configs(campaign int) ([][]byte, error)
+
+	// AddCampaign - Store a new campaign, names are unique
+	AddCampaign(c Campaign) (int, error)
+	// CampaignByName - Returns the campaign, its ID is 0 if there is none
+	CampaignByName(name string) (Campaign, error)
+	// Campaigns - Returns all campaigns in order of creation
+	Campaigns() ([]Campaign, error)
+
+	// AddDefaultConfig - Store a default config blob with name
+	AddDefaultConfig(name string, blob []byte) error
+	// DefaultConfig - Returns the default config blob with name
+	DefaultConfig(name string) ([]byte, error)
+
+	// SetCompleteLog - Store the raw console log of a test
+	SetCompleteLog(testID int, completeLog []byte) error
+	// CompleteLog - Returns the raw console log of a test
+	CompleteLog(testID int) ([]byte, error)
+	// SetAttempts - Replace the attempts made to collect a test
+	SetAttempts(testID int, attempts []tracelog.Attempt) error
+	// Attempts - Returns the attempts made to collect a test
+	Attempts(testID int) ([]tracelog.Attempt, error)
+	// AddCommands - Store DUT control commands run for a test
+	AddCommands(testID int, commands []tracelog.CommandResult) error
+
+	// WriteEntries - Append trace entries of a test in a single transaction
+	WriteEntries(testID int, entries []tracelog.TraceLogEntry) error
+	// ResetEntries - Remove all trace entries of a test
+	ResetEntries(testID int) error
+	// Entries - Returns the trace entries of a test in capture order
+	Entries(testID int) ([]tracelog.TraceLogEntry, error)
+
+	// AddFakeRules - Store BL_FAKE rules of a test
+	AddFakeRules(testID int, rules []tracelog.FakeRule) error
+	// FakeRules - Returns the BL_FAKE rules of a test
+	FakeRules(testID int) ([]tracelog.FakeRule, error)
+	// SetFilters - Replace the BL_FILTER filters of a test
+	SetFilters(testID int, filters []tracelog.Filter) error
+	// Filters - Returns the BL_FILTER filters of a test
+	Filters(testID int) ([]tracelog.Filter, error)
+
+	// Migrate - Create the schema or update it to SchemaVersion
+	Migrate() error
+	// Close - Release the connection or file
+	Close() error
+}
+
+// OpenStore - Opens the backend selected by the database section of cfg
+func OpenStore(cfg config.Config) (Store, error) {
+	switch cfg.Database.Driver {
+	case "", "mysql":
+		return openMysqlStore(cfg)
+	case "file":
+		return openFileStore(cfg.Database.Path)
+	}
+	return nil, fmt.Errorf("Unknown database driver %q", cfg.Database.Driver)
+}
@@ -1,10 +1,8 @@
 package test
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/9elements/autorev/tracelog"
 
@@ -15,40 +13,41 @@ import (
 type test struct {
 	// Latest Test id we fetched
 	LatestTestID int
-	// Database Connection
-	db *sql.DB
+	// Backend the tests are stored in
+	store Store
+	// Campaign tests are added to and taken from, ID 0 for all tests
+	campaign Campaign
 	// Config
 	cfg config.Config
 }
 
 // Close - Close DB Connection
 func (t *test) Close() {
-	t.db.Close()
+	t.store.Close()
 }
 
 // Init = Initialize a new Test
 func Init(cfg config.Config) (*test, error) {
-	var err error
-
-	t := test{
-		LatestTestID: -1,
-		db:           nil,
-	}
-	t.cfg = cfg
-
-	log.Println("Seting up database connection..")
-	// Setup Mysql Connection
-	t.db, err = sql.Open("mysql", fmt.Sprintf("%s:%s@/autorev", t.cfg.Database.Username, t.cfg.Database.Password))
-
+	store, err := OpenStore(cfg)
 	if err != nil {
 		return nil, err
 	}
-	err = t.db.Ping()
-	if err != nil {
-		return nil, err
+
+	return NewWithStore(cfg, store), nil
+}
+
+// NewWithStore - Initialize a new Test using an already opened store
+func NewWithStore(cfg config.Config, store Store) *test {
+	return &test{
+		LatestTestID: -1,
+		store:        store,
+		cfg:          cfg,
 	}
+}
 
-	return &t, nil
+// Migrate - Create the database schema or update an existing database
+func (t *test) Migrate() error {
+	return t.store.Migrate()
 }
 
 // GetLatestTestID - Fetch Latest Test ID from Struct
@@ -63,115 +62,149 @@ func (t *test) SetLatestTestID(val int) {
 
 // GetNextTest - Get next free test
 func (t *test) GetNextTest() (int, error) {
-	if t.db == nil {
-		return -1, fmt.Errorf("DB Function Pointer is nil")
-	}
-
-	// Fetch latest test, update and grab upd config
-	nextTest, err := t.db.Query("SELECT idTests FROM tests WHERE status = 0 ORDER BY ts_added ASC LIMIT 1;")
+	id, err := t.store.NextTest(t.campaign.ID)
 	if err != nil {
 		return -1, err
 	}
+	if id == 0 {
+		return 0, nil
+	}
 
-	columns, err := nextTest.Columns()
+	t.LatestTestID = id
+	log.Printf("Next Test: %d\n", t.LatestTestID)
 
-	values := make([]sql.RawBytes, len(columns))
+	return t.LatestTestID, nil
+}
 
-	scanArgs := make([]interface{}, len(values))
-	for i := range values {
-		scanArgs[i] = &values[i]
+// ClaimNextTest - Atomically fetch the next free test and set it in progress
+// The DUT name is stored with the test. Returns 0 if all tests have been run.
+// Concurrent workers never get the same test.
+func (t *test) ClaimNextTest(dut string) (int, error) {
+	id, err := t.store.ClaimNextTest(t.campaign.ID, dut)
+	if err != nil {
+		return -1, err
 	}
-
-	if nextTest.Next() {
-		err = nextTest.Scan(scanArgs...)
-	} else {
+	if id == 0 {
 		return 0, nil
 	}
 
-	t.LatestTestID, err = strconv.Atoi(string(values[0]))
-	if err != nil {
-		return -1, err
-	}
-	log.Printf("Next Test: %d\n", t.LatestTestID)
+	t.LatestTestID = id
+	log.Printf("Next Test: %d on %s\n", t.LatestTestID, dut)
 
 	return t.LatestTestID, nil
 }
 
-// SetTestInProgress - Update Test to be in Progress
-func (t *test) SetTestInProgress() error {
+// Worker - Returns a copy sharing the store, with its own latest test
+// Every goroutine collecting traces needs its own copy.
+func (t *test) Worker() *test {
+	w := NewWithStore(t.cfg, t.store)
+	w.campaign = t.campaign
+	return w
+}
 
-	stmtUpdate, err := t.db.Prepare("UPDATE tests SET status = 1, ts_started = NOW() WHERE idTests = ?")
+// UseCampaign - Select the campaign all following commands work on
+// If create is set a missing campaign is created with a snapshot of the trace
+// log settings of cfg and its default config. Returns cfg with the settings of
+// the campaign.
+func (t *test) UseCampaign(name string, cfg config.Config, create bool) (config.Config, error) {
+	c, err := t.store.CampaignByName(name)
 	if err != nil {
-		return err
+		return cfg, err
 	}
-	stmtUpdate.Exec(t.LatestTestID)
 
-	return nil
-}
+	snapshot, err := cfg.CampaignSnapshot()
+	if err != nil {
+		return cfg, err
+	}
 
-// SetTestSuccessful - Update Test to failed
-func (t *test) SetTestSuccessful() error {
+	if c.ID == 0 {
+		if !create {
+			return cfg, fmt.Errorf("Campaign %s not found", name)
+		}
+		c = Campaign{Name: name, Snapshot: snapshot}
+		c.DefaultBlob, err = t.store.DefaultConfig(cfg.TraceLog.OptionsDefaultTable)
+		if err != nil {
+			return cfg, fmt.Errorf("Failed to fetch default config %s: %v", cfg.TraceLog.OptionsDefaultTable, err)
+		}
+		c.ID, err = t.store.AddCampaign(c)
+		if err != nil {
+			return cfg, err
+		}
+		log.Printf("Created campaign %s\n", name)
+	} else if string(snapshot) != string(c.Snapshot) {
+		log.Printf("config.yml differs from campaign %s, using the settings of the campaign\n", name)
+	}
 
-	stmtUpdate, err := t.db.Prepare("UPDATE tests SET status = 2, ts_finished = NOW() WHERE idTests = ?")
+	cfg, err = cfg.WithCampaign(c.Snapshot)
 	if err != nil {
-		return err
+		return cfg, fmt.Errorf("Invalid snapshot of campaign %s: %v", name, err)
 	}
-	stmtUpdate.Exec(t.LatestTestID)
+	t.campaign = c
+	t.cfg = cfg
 
-	return nil
+	return cfg, nil
+}
+
+// GetCampaigns - Fetches all campaigns
+func (t *test) GetCampaigns() ([]Campaign, error) {
+	return t.store.Campaigns()
+}
+
+// SetTestInProgress - Update Test to be in Progress
+func (t *test) SetTestInProgress() error {
+	return t.store.SetStatus(t.LatestTestID, StatusRunning)
+}
+
+// SetTestSuccessful - Update Test to failed
+func (t *test) SetTestSuccessful() error {
+	return t.store.SetStatus(t.LatestTestID, StatusSuccessful)
 }
 
 // SetTestFailed - Update Test to failed
 func (t *test) SetTestFailed() error {
+	return t.store.SetStatus(t.LatestTestID, StatusFailed)
+}
 
-	stmtUpdate, err := t.db.Prepare("UPDATE tests SET status = 3, ts_finished = NOW() WHERE idTests = ?")
-	if err != nil {
-		return err
-	}
-	stmtUpdate.Exec(t.LatestTestID)
+// SetCompleteLog - Store the raw console log of the latest test
+func (t *test) SetCompleteLog(completeLog []byte) error {
+	return t.store.SetCompleteLog(t.LatestTestID, completeLog)
+}
 
-	return nil
+// GetCompleteLog - Fetch the raw console log of a test
+func (t *test) GetCompleteLog(testID int) ([]byte, error) {
+	return t.store.CompleteLog(testID)
 }
 
-// GenNewTest - Insert a test into DB
-func (t *test) GenNewTest(name string, config config.Config, configBlob []byte) error {
-	if t.db == nil {
-		return fmt.Errorf("DB Function Pointer is nil")
-	}
+// SetAttempts - Store the attempts made to collect the latest test
+// The failure reason of the last attempt is stored with the test.
+func (t *test) SetAttempts(attempts []tracelog.Attempt) error {
+	return t.store.SetAttempts(t.LatestTestID, attempts)
+}
 
-	stmt, err := t.db.Prepare("INSERT INTO tests (status, ts_added, config, fk_defaultConfig) VALUES (0, NOW(), ?, (SELECT updId FROM updDefaults WHERE platformName = ?))")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+// GetAttempts - Fetches the attempts made to collect a test
+func (t *test) GetAttempts(testID int) ([]tracelog.Attempt, error) {
+	return t.store.Attempts(testID)
+}
+
+// AddCommands - Store the DUT control commands run for the latest test
+func (t *test) AddCommands(commands []tracelog.CommandResult) error {
+	return t.store.AddCommands(t.LatestTestID, commands)
+}
 
-	_, err = stmt.Exec(configBlob, config.TraceLog.OptionsDefaultTable)
+// GenNewTest - Insert a test into DB and set LatestTestID to the new test
+func (t *test) GenNewTest(name string, config config.Config, configBlob []byte) error {
+	id, err := t.store.AddTest(t.campaign.ID, configBlob, config.TraceLog.OptionsDefaultTable)
 	if err != nil {
 		return err
 	}
+	t.LatestTestID = id
+
 	return nil
 }
 
 // SetNewDefaultConfig - Add a new default config with name to the DB
 func (t *test) SetNewDefaultConfig(name string, config []byte) error {
-	/*
-		if t.db == nil {
-			return fmt.Errorf("DB Function Pointer is nil")
-		}
-
-		if t.LatestTestID == -1 {
-			return fmt.Errorf("Latest Test ID is not set")
-		}
-	*/
-
-	// TODO: Check if platform already exists - ask to overwrite, cancel or new name
-
-	stmt, err := t.db.Prepare("INSERT INTO `updDefaults` (`platformName`, `size`, `configBlob`) VALUES (?, ?, ?)")
-	if err != nil {
-		return err
-	}
-
-	_, err = stmt.Exec(name, len(config), config)
+	err := t.store.AddDefaultConfig(name, config)
 	if err != nil {
 		return err
 	}
@@ -181,244 +214,230 @@ func (t *test) SetNewDefaultConfig(name string, config []byte) error {
 }
 
 // GetDefaultConfig - Fetches the default config for a given name
+// Within a campaign the default config of the campaign is returned.
 func (t *test) GetDefaultConfig(name string) ([]byte, error) {
-	if t.db == nil {
-		return nil, fmt.Errorf("DB Function Pointer is nil")
-	}
-
-	// Get Size of DefaultConfig
-	stmt, err := t.db.Prepare("SELECT size FROM updDefaults WHERE platformName = ?")
-
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	var amount int
-
-	err = stmt.QueryRow(name).Scan(&amount)
-	if err != nil {
-		return nil, err
-	}
-
-	// Fetch Default Config
-	config := make([]byte, amount)
-
-	scanArgs := make([]interface{}, 1)
-	scanArgs[0] = &config[0]
-
-	stmtConfig, err := t.db.Prepare("SELECT configBlob FROM updDefaults WHERE platformName = ? LIMIT 1")
-
-	if err != nil {
-		return nil, err
-	}
-
-	defer stmtConfig.Close()
-
-	err = stmtConfig.QueryRow(name).Scan(&config)
-	if err != nil {
-		return nil, err
+	if t.campaign.ID > 0 {
+		return append([]byte(nil), t.campaign.DefaultBlob...), nil
 	}
-
-	return config, nil
-
+	return t.store.DefaultConfig(name)
 }
 
 // GetConfig - Fetch the config from the last test
 func (t *test) GetConfig(testID int) ([]byte, error) {
-	if t.db == nil {
-		return nil, fmt.Errorf("DB Function Pointer is nil")
-	}
-
 	if testID == -1 {
 		return nil, fmt.Errorf("Invalid testID")
 	}
 
-	// Get Size of DefaultConfig
-	stmt, err := t.db.Prepare("SELECT size FROM updDefaults WHERE platformName = ?")
-
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
+	return t.store.Config(testID)
+}
 
-	var amount int
-	name := t.cfg.TraceLog.OptionsDefaultTable
+// WriteSetIntoDB - write a TraceLogEntry Set into the DB with fk = LasttestTestID123
+// All entries are written in a single transaction.
+func (t *test) WriteSetIntoDB(entries []tracelog.TraceLogEntry) error {
+	return t.store.WriteEntries(t.LatestTestID, entries)
+}
 
-	err = stmt.QueryRow(name).Scan(&amount)
-	if err != nil {
-		return nil, err
-	}
+// traceWriter - Stores streamed entries of a single test, see tracelog.EntryWriter
+type traceWriter struct {
+	store  Store
+	testID int
+}
 
-	// Fetch Default Config
-	config := make([]byte, amount)
+func (w *traceWriter) WriteEntries(entries []tracelog.TraceLogEntry) error {
+	return w.store.WriteEntries(w.testID, entries)
+}
 
-	scanArgs := make([]interface{}, 1)
-	scanArgs[0] = &config[0]
+func (w *traceWriter) ResetEntries() error {
+	return w.store.ResetEntries(w.testID)
+}
 
-	stmtConfig, err := t.db.Prepare("SELECT config FROM tests WHERE idTests = ? LIMIT 1")
+// TraceWriter - Returns a writer storing entries into the latest test
+func (t *test) TraceWriter() tracelog.EntryWriter {
+	return &traceWriter{store: t.store, testID: t.LatestTestID}
+}
 
+// AddFakeRules - Add BL_FAKE rules to the latest test. Rules already present are skipped
+func (t *test) AddFakeRules(rules []tracelog.FakeRule) error {
+	existing, err := t.GetFakeRules(t.LatestTestID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	defer stmtConfig.Close()
-
-	err = stmtConfig.QueryRow(testID).Scan(&config)
-	if err != nil {
-		return nil, err
+	var add []tracelog.FakeRule
+	for _, rule := range rules {
+		found := false
+		for _, e := range existing {
+			if e == rule {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		add = append(add, rule)
+		existing = append(existing, rule)
+	}
+	if len(add) == 0 {
+		return nil
 	}
 
-	return config, nil
+	return t.store.AddFakeRules(t.LatestTestID, add)
 }
 
-// WriteSetIntoDB - write a TraceLogEntry Set into the DB with fk = LasttestTestID123
-func (t *test) WriteSetIntoDB(entries []tracelog.TraceLogEntry) error {
-
-	stmt, err := t.db.Prepare("INSERT INTO traceLog (type, input, address, value, ip, accessSize, fk_idTests) VALUES (?, ?, ?, ?, ?, ?, ?)")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+// GetFakeRules - Fetches the BL_FAKE rules of a test
+func (t *test) GetFakeRules(testID int) ([]tracelog.FakeRule, error) {
+	return t.store.FakeRules(testID)
+}
 
-	for _, entry := range entries {
-		_, err = stmt.Exec(entry.Type, entry.Inout, entry.Address, entry.Value, entry.IP, entry.AccessSize, t.LatestTestID)
-		if err != nil {
-			return err
-		}
-	}
+// SetFilters - Store the BL_FILTER filters active for the latest test
+func (t *test) SetFilters(filters []tracelog.Filter) error {
+	return t.store.SetFilters(t.LatestTestID, filters)
+}
 
-	return nil
+// GetFilters - Fetches the BL_FILTER filters that were active for a test
+func (t *test) GetFilters(testID int) ([]tracelog.Filter, error) {
+	return t.store.Filters(testID)
 }
 
 // FetchTraceLogEntriesFromDB - Fetches TraceLogEntries from the DB for a given test testID
 func (t *test) FetchTraceLogEntriesFromDB(testID int) ([]tracelog.TraceLogEntry, error) {
-	if t.db == nil {
-		return nil, fmt.Errorf("DB Function Pointer is nil")
-	}
+	return t.store.Entries(testID)
+}
 
-	var traceLogEntries []tracelog.TraceLogEntry
+// FetchTraceLogEntriesFromDB - Fetches TraceLogEntries from the DB for a given test testID
+func (t *test) FetchSuccessfulTraceLogIDFromDB() ([]int, error) {
+	return t.store.SuccessfulTests(t.campaign.ID)
+}
 
-	rows, err := t.db.Query("SELECT type, input, address, value, ip, accessSize FROM traceLog WHERE fk_idTests = ? ORDER BY idTraceLog ASC", testID)
+// FetchSuccessfulTestsByConfigFromDB - Groups successful tests by their config blob
+// Groups with more than one test are repeated runs of the same config.
+func (t *test) FetchSuccessfulTestsByConfigFromDB() ([][]int, error) {
+	ids, err := t.store.SuccessfulTests(t.campaign.ID)
 	if err != nil {
 		return nil, err
 	}
-	values := make([]uint64, 6)
-	scanArgs := make([]interface{}, len(values))
-
-	for i := range values {
-		scanArgs[i] = &values[i]
-	}
 
-	for {
-		if rows.Next() {
-			err = rows.Scan(scanArgs...)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			break
+	var groups [][]int
+	index := map[string]int{}
+	for _, id := range ids {
+		blob, err := t.store.Config(id)
+		if err != nil {
+			return nil, err
 		}
-
-		tracelogentry := tracelog.TraceLogEntry{
-			Type:       int(values[0]),
-			Inout:      (values[1] != 0),
-			Address:    uint(values[2]),
-			Value:      uint64(values[3]),
-			IP:         uint(values[4]),
-			AccessSize: uint(values[5]),
+		i, ok := index[string(blob)]
+		if !ok {
+			i = len(groups)
+			index[string(blob)] = i
+			groups = append(groups, nil)
 		}
-		traceLogEntries = append(traceLogEntries, tracelogentry)
+		groups[i] = append(groups[i], id)
 	}
-	return traceLogEntries, nil
+	return groups, nil
 }
 
-// FetchTraceLogEntriesFromDB - Fetches TraceLogEntries from the DB for a given test testID
-func (t *test) FetchSuccessfulTraceLogIDFromDB() ([]int, error) {
-	if t.db == nil {
-		return nil, fmt.Errorf("DB Function Pointer is nil")
-	}
-
-	var traceLogIDs []int
-
-	rows, err := t.db.Query("SELECT idTests FROM tests WHERE status = 2")
+// GenNewTestsFromCfg - Creates new tests based on user provided FirmwareOption config
+// The tests are generated by the strategy of the generation section, the
+// number of tests and their interaction coverage is logged before they are
+// inserted. With dryRun set nothing is inserted. Returns the number of tests.
+func (t *test) GenNewTestsFromCfg(cfg config.Config, blob []byte, dryRun bool) (uint, error) {
+	opts := cfg.TraceLog.VariableFirmareOptions
+	gen := cfg.TraceLog.Generation
+	err := checkOptions(cfg, blob)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	values := make([]uint64, 1)
-	scanArgs := make([]interface{}, len(values))
-
-	for i := range values {
-		scanArgs[i] = &values[i]
+	if gen.Strategy == GenerateAdaptive {
+		return t.genAdaptiveTests(cfg, blob, dryRun)
 	}
 
-	for {
-		if rows.Next() {
-			err = rows.Scan(scanArgs...)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			break
-		}
-
-		traceLogIDs = append(traceLogIDs, int(values[0]))
+	sizes := make([]int, len(opts))
+	for i, opt := range opts {
+		sizes[i] = int(opt.Max-opt.Min) + 1
 	}
-	return traceLogIDs, nil
-}
 
-// GenRecursiveNewTestsFromCfg - Recursivly creates new tests based on user provided FirmwareOption config
-func (t *test) GenRecursiveNewTestsFromCfg(name string, cfg config.Config, blob []byte, level int) (uint, error) {
-	var cnt uint
-	if level > len(cfg.TraceLog.VariableFirmareOptions) {
-		// should not happen
-		return cnt, nil
-	} else if level == len(cfg.TraceLog.VariableFirmareOptions) {
-		err := t.GenNewTest(name, cfg, blob)
-		cnt++
-		return cnt, err
+	tests, err := GenerateTests(sizes, gen.Strategy, gen.Strength, gen.Samples, gen.Seed)
+	if err != nil {
+		return 0, err
 	}
 
-	opt := cfg.TraceLog.VariableFirmareOptions[level]
-
-	if opt.ByteOffset > uint(len(blob)) {
-		log.Printf("Invalid ByteOffset specified for %s\n", opt.Name)
+	strategy := gen.Strategy
+	if len(strategy) == 0 {
+		strategy = GenerateFull
 	}
-	if opt.BitWidth > 64 {
-		log.Printf("Invalid BitWidth specified for %s\n", opt.Name)
+	full := 1
+	for _, size := range sizes {
+		full *= size
 	}
-	if opt.Max < opt.Min {
-		log.Printf("Invalid BitWidth specified for %s\n", opt.Name)
+	maxStrength := 3
+	if strategy == GenerateTWise && gen.Strength > maxStrength {
+		maxStrength = gen.Strength
 	}
+	log.Printf("Strategy %s generated %d of %d configs, coverage %s\n", strategy, len(tests), full,
+		CoverageReport(sizes, tests, maxStrength))
 
-	for j := opt.Min; j <= opt.Max; j++ {
-		blobcopy := blob
+	repeat := cfg.TraceLog.Repeat
+	if repeat == 0 {
+		repeat = 1
+	}
+	if dryRun {
+		return uint(len(tests)) * repeat, nil
+	}
 
-		for a := uint(0); a*8 < opt.BitWidth; a++ {
-			blobcopy[opt.ByteOffset+a] = byte((uint64(j) >> (a * 8)))
+	var cnt uint
+	for _, test := range tests {
+		options := map[string]uint64{}
+		for i, opt := range opts {
+			options[opt.Name] = opt.Min + uint64(test[i])
 		}
+		blobcopy, name := blobWithOptions(cfg, blob, options)
 
-		i, err := t.GenRecursiveNewTestsFromCfg(name+fmt.Sprintf("%s=%d ", opt.Name, j), cfg, blobcopy, level+1)
-		cnt += i
-		if err != nil {
-			return cnt, err
+		// Repeated runs of the same config reveal non-deterministic accesses
+		for r := uint(0); r < repeat; r++ {
+			err := t.GenNewTest(name, cfg, blobcopy)
+			if err != nil {
+				return cnt, err
+			}
+			cnt++
 		}
 	}
 
 	return cnt, nil
 }
 
+// checkOptions - Returns an error if a FirmwareOption doesn't fit into blob or
+// has no values
+func checkOptions(cfg config.Config, blob []byte) error {
+	for _, opt := range cfg.TraceLog.VariableFirmareOptions {
+		if opt.BitWidth > 64 {
+			return fmt.Errorf("Invalid BitWidth specified for %s", opt.Name)
+		}
+		if opt.ByteOffset+(opt.BitWidth+7)/8 > uint(len(blob)) {
+			return fmt.Errorf("Invalid ByteOffset specified for %s", opt.Name)
+		}
+		if opt.Max < opt.Min {
+			return fmt.Errorf("Invalid Max specified for %s, it's below Min", opt.Name)
+		}
+	}
+	return nil
+}
+
 // GetFirmwareOptionsFromConfigBLOBs - Convert blob config of test "testID" to map of UPDs
 func (t *test) GetFirmwareOptionsFromConfigBLOBs(cfg config.Config, testID int) (map[string]uint64, error) {
-	optionsset := map[string]uint64{}
-
 	currentblob, err := t.GetConfig(testID)
 	if err != nil {
 		return nil, err
 	}
 
+	return optionsFromBlob(cfg, currentblob), nil
+}
+
+// optionsFromBlob - Convert a config blob to map of UPDs
+func optionsFromBlob(cfg config.Config, blob []byte) map[string]uint64 {
+	optionsset := map[string]uint64{}
+
 	for _, opt := range cfg.TraceLog.VariableFirmareOptions {
-		if opt.ByteOffset > uint(len(currentblob)) {
+		if opt.ByteOffset > uint(len(blob)) {
 			log.Printf("Invalid ByteOffset specified for %s\n", opt.Name)
 			continue
 		}
@@ -433,10 +452,28 @@ func (t *test) GetFirmwareOptionsFromConfigBLOBs(cfg config.Config, testID int)
 
 		var val uint64
 		for a := uint(0); a*8 < opt.BitWidth; a++ {
-			val |= uint64(currentblob[opt.ByteOffset+a]) << (a * 8)
+			val |= uint64(blob[opt.ByteOffset+a]) << (a * 8)
 		}
 		optionsset[opt.Name] = val
 	}
 
-	return optionsset, nil
+	return optionsset
+}
+
+// blobWithOptions - Returns a copy of blob with the UPDs in options set and the
+// test name listing them
+func blobWithOptions(cfg config.Config, blob []byte, options map[string]uint64) ([]byte, string) {
+	blobcopy := append([]byte(nil), blob...)
+	name := ""
+	for _, opt := range cfg.TraceLog.VariableFirmareOptions {
+		val, ok := options[opt.Name]
+		if !ok {
+			continue
+		}
+		for a := uint(0); a*8 < opt.BitWidth; a++ {
+			blobcopy[opt.ByteOffset+a] = byte(val >> (a * 8))
+		}
+		name += fmt.Sprintf("%s=%d ", opt.Name, val)
+	}
+	return blobcopy, name
 }
@@ -0,0 +1,244 @@
+package tracelog
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Binary trace frames:
+//
+//	sync   2 bytes 0xb7 0x1e
+//	length 1 byte, length of payload
+//	payload:
+//	  tds  1 byte, bit 0-2 type, bit 3 direction (1: in), bit 4-6 access size code,
+//	       bit 7 TSC present
+//	  ip, address, value as unsigned LEB128 varints
+//	  CPUID only: subleaf, ebx, ecx, edx as unsigned LEB128 varints, value is eax
+//	  STRIO only: count as unsigned LEB128 varint
+//	  tsc as unsigned LEB128 varint if bit 7 of tds is set
+//	crc    2 bytes CRC-16/CCITT-FALSE over length and payload, little endian
+const (
+	binarySync0 = 0xb7
+	binarySync1 = 0x1e
+	// sync + length + crc
+	binaryFrameOverhead = 5
+)
+
+// Access size codes used in the tds byte
+var binaryAccessSizes = []uint{0, 8, 16, 32, 64}
+
+// crc16 - CRC-16/CCITT-FALSE
+func crc16(data []byte) uint16 {
+	crc := uint16(0xffff)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// EncodeBinaryEntry - Encode TraceLogEntry as binary trace frame
+// This is what the DUT sends if binary tracing has been negotiated.
+func EncodeBinaryEntry(tle *TraceLogEntry) ([]byte, error) {
+	sizeCode := -1
+	for i, s := range binaryAccessSizes {
+		if s == tle.AccessSize {
+			sizeCode = i
+			break
+		}
+	}
+	if sizeCode == -1 {
+		return nil, fmt.Errorf("Access size %d can't be encoded", tle.AccessSize)
+	}
+	if tle.Type < 0 || tle.Type > 7 {
+		return nil, fmt.Errorf("Type %d can't be encoded", tle.Type)
+	}
+
+	tds := byte(tle.Type) | byte(sizeCode)<<4
+	if tle.Inout {
+		tds |= 1 << 3
+	}
+	if tle.TSC != 0 {
+		tds |= 1 << 7
+	}
+	payload := []byte{tds}
+	payload = binary.AppendUvarint(payload, uint64(tle.IP))
+	payload = binary.AppendUvarint(payload, uint64(tle.Address))
+	payload = binary.AppendUvarint(payload, tle.Value)
+	if tle.Type == int(CPUID) {
+		payload = binary.AppendUvarint(payload, uint64(tle.Subleaf))
+		for _, r := range tle.Regs[1:] {
+			payload = binary.AppendUvarint(payload, uint64(r))
+		}
+	}
+	if tle.Type == int(STRIO) {
+		payload = binary.AppendUvarint(payload, uint64(tle.Count))
+	}
+	if tle.TSC != 0 {
+		payload = binary.AppendUvarint(payload, tle.TSC)
+	}
+
+	frame := []byte{binarySync0, binarySync1, byte(len(payload))}
+	frame = append(frame, payload...)
+	crc := crc16(frame[2:])
+	return append(frame, byte(crc), byte(crc>>8)), nil
+}
+
+// decodeBinaryPayload - Decode the payload of a binary trace frame
+func decodeBinaryPayload(payload []byte) (*TraceLogEntry, error) {
+	var tle TraceLogEntry
+
+	if len(payload) < 4 {
+		return nil, fmt.Errorf("Binary frame too short")
+	}
+	tds := payload[0]
+	tle.Type = int(tds & 7)
+	if ConvertFromType(tle.Type) == "" {
+		return nil, fmt.Errorf("Binary frame has unknown type value")
+	}
+	tle.Inout = tds&(1<<3) != 0
+	sizeCode := int(tds>>4) & 7
+	if sizeCode >= len(binaryAccessSizes) {
+		return nil, fmt.Errorf("Binary frame has unknown access size")
+	}
+	tle.AccessSize = binaryAccessSizes[sizeCode]
+
+	p := payload[1:]
+	var values []uint64
+	for len(p) > 0 && len(values) < 8 {
+		v, n := binary.Uvarint(p)
+		if n <= 0 {
+			return nil, fmt.Errorf("Binary frame has malformed varint")
+		}
+		values = append(values, v)
+		p = p[n:]
+	}
+	if tds&(1<<7) != 0 {
+		if len(values) < 4 {
+			return nil, fmt.Errorf("Binary frame too short")
+		}
+		tle.TSC = values[len(values)-1]
+		values = values[:len(values)-1]
+	}
+	// CPUID frames of old DUTs only carry eax
+	want := 3
+	if tle.Type == int(CPUID) && len(values) > 3 {
+		want = 7
+	} else if tle.Type == int(STRIO) {
+		want = 4
+	}
+	if len(values) < want {
+		return nil, fmt.Errorf("Binary frame too short")
+	}
+	if len(values) > want || len(p) != 0 {
+		return nil, fmt.Errorf("Binary frame has trailing bytes")
+	}
+	tle.IP = uint(values[0])
+	tle.Address = uint(values[1])
+	tle.Value = values[2]
+	if tle.Type == int(CPUID) {
+		tle.Regs[0] = uint32(tle.Value)
+		if want == 7 {
+			tle.Subleaf = uint(values[3])
+			tle.Regs[1] = uint32(values[4])
+			tle.Regs[2] = uint32(values[5])
+			tle.Regs[3] = uint32(values[6])
+		}
+	}
+	if tle.Type == int(STRIO) {
+		tle.Count = uint(values[3])
+	}
+
+	return &tle, nil
+}
+
+// binaryDecoder - Splits a byte stream into binary trace frames and text lines
+type binaryDecoder struct {
+	// text received outside of frames
+	text []byte
+	// current frame, starting with the sync word
+	frame []byte
+	// bytes of a corrupted frame to decode again
+	replay []byte
+}
+
+// binaryItem - A decoded entry, a complete text line (without newline) or an
+// error on a corrupted frame
+type binaryItem struct {
+	tle  *TraceLogEntry
+	line *string
+	err  error
+}
+
+// feed - Add one byte received from the DUT. Returns the items completed by it.
+// The bytes of a frame with wrong CRC following its sync word are decoded
+// again starting at the next sync word, so a corrupted length doesn't swallow
+// the frames after it.
+func (d *binaryDecoder) feed(b byte) []binaryItem {
+	var items []binaryItem
+
+	d.replay = append(d.replay, b)
+	for len(d.replay) > 0 {
+		b := d.replay[0]
+		d.replay = d.replay[1:]
+		tle, line, err := d.step(b)
+		if tle != nil || line != nil || err != nil {
+			items = append(items, binaryItem{tle: tle, line: line, err: err})
+		}
+	}
+	return items
+}
+
+// step - Decode one byte, see feed
+func (d *binaryDecoder) step(b byte) (tle *TraceLogEntry, line *string, err error) {
+	if len(d.frame) == 0 {
+		if b == binarySync0 {
+			d.frame = append(d.frame, b)
+			return nil, nil, nil
+		}
+		if b == '\r' {
+			return nil, nil, nil
+		}
+		if b == '\n' {
+			s := string(d.text)
+			d.text = d.text[:0]
+			return nil, &s, nil
+		}
+		d.text = append(d.text, b)
+		return nil, nil, nil
+	}
+
+	if len(d.frame) == 1 && b != binarySync1 {
+		// Not a frame, treat as text
+		d.text = append(d.text, d.frame[0])
+		d.frame = d.frame[:0]
+		return d.step(b)
+	}
+
+	d.frame = append(d.frame, b)
+	if len(d.frame) < 3 || len(d.frame) < int(d.frame[2])+binaryFrameOverhead {
+		return nil, nil, nil
+	}
+
+	frame := d.frame
+	d.frame = nil
+	crc := crc16(frame[2 : len(frame)-2])
+	if byte(crc) != frame[len(frame)-2] || byte(crc>>8) != frame[len(frame)-1] {
+		rest := frame[1:]
+		for i := range rest {
+			if rest[i] == binarySync0 && (i == len(rest)-1 || rest[i+1] == binarySync1) {
+				d.replay = append(append([]byte{}, rest[i:]...), d.replay...)
+				break
+			}
+		}
+		return nil, nil, fmt.Errorf("Binary frame has wrong CRC")
+	}
+	tle, err = decodeBinaryPayload(frame[3 : len(frame)-2])
+	return tle, nil, err
+}
@@ -0,0 +1,127 @@
+package tracelog
+
+import "testing"
+
+func TestBinaryRoundtrip(t *testing.T) {
+	entries := []TraceLogEntry{
+		{IP: 0xfffff000, Type: int(MEM32), Inout: true, Address: 0xfed40000, Value: 0xffffffff, AccessSize: 32},
+		{IP: 0x1234, Type: int(IO), Inout: false, Address: 0x80, Value: 0xddaa, AccessSize: 16},
+		{IP: 0x1234, Type: int(MSR), Inout: true, Address: 0x1b, Value: 0xfee0090000000000},
+		{IP: 0x1234, Type: int(MEM64), Inout: false, Address: 0x4000000000, Value: 0x8000000000000001, AccessSize: 64},
+		{IP: 0x1234, Type: int(STRIO), Inout: true, Address: 0x1f0, Value: 0x7c00, Count: 256, AccessSize: 16},
+		{IP: 0x1234, Type: int(CPUID), Inout: true, Address: 7, Value: 1, Subleaf: 0, Regs: [4]uint32{1, 0x029c6fbf, 0x40000000, 0xbc000400}},
+	}
+
+	var d binaryDecoder
+	var stream []byte
+	for i := range entries {
+		frame, err := EncodeBinaryEntry(&entries[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream = append(stream, frame...)
+		stream = append(stream, []byte("debug output\r\n")...)
+	}
+
+	var got []TraceLogEntry
+	var lines []string
+	for _, b := range stream {
+		for _, item := range d.feed(b) {
+			if item.err != nil {
+				t.Fatal(item.err)
+			}
+			if item.tle != nil {
+				got = append(got, *item.tle)
+			}
+			if item.line != nil {
+				lines = append(lines, *item.line)
+			}
+		}
+	}
+
+	if len(got) != len(entries) {
+		t.Fatalf("Decoded %d entries, want %d", len(got), len(entries))
+	}
+	for i := range got {
+		if got[i] != entries[i] {
+			t.Errorf("Decoded %s, want %s", got[i].String(), entries[i].String())
+		}
+	}
+	if len(lines) != 6 || lines[0] != "debug output" {
+		t.Errorf("Wrong text lines %q", lines)
+	}
+}
+
+func TestBinaryCRCError(t *testing.T) {
+	frame, _ := EncodeBinaryEntry(&TraceLogEntry{Type: int(IO), Address: 0x80, AccessSize: 8})
+	frame[4] ^= 1
+
+	var d binaryDecoder
+	var err error
+	for _, b := range frame {
+		for _, item := range d.feed(b) {
+			if item.err != nil {
+				err = item.err
+			}
+		}
+	}
+	if err == nil {
+		t.Errorf("Expected CRC error")
+	}
+}
+
+func TestBinaryResync(t *testing.T) {
+	entries := []TraceLogEntry{
+		{Type: int(IO), Address: 0x80, Value: 1, AccessSize: 8},
+		{Type: int(IO), Address: 0x80, Value: 2, AccessSize: 8},
+		{Type: int(IO), Address: 0x80, Value: 3, AccessSize: 8},
+	}
+	var stream []byte
+	for i := range entries {
+		frame, err := EncodeBinaryEntry(&entries[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			// Corrupted length covering the following frames
+			frame[2] = 0xff
+		}
+		stream = append(stream, frame...)
+	}
+	stream = append(stream, []byte("done\r\n")...)
+
+	var d binaryDecoder
+	var got []TraceLogEntry
+	var lines []string
+	errors := 0
+	feed := func(b byte) {
+		for _, item := range d.feed(b) {
+			if item.err != nil {
+				errors++
+			}
+			if item.tle != nil {
+				got = append(got, *item.tle)
+			}
+			if item.line != nil {
+				lines = append(lines, *item.line)
+			}
+		}
+	}
+	for _, b := range stream {
+		feed(b)
+	}
+	// The stream ends within the corrupted frame, complete it with padding
+	for i := 0; i < 0xff && len(lines) == 0; i++ {
+		feed(0)
+	}
+
+	if errors != 1 {
+		t.Errorf("Got %d errors, want 1", errors)
+	}
+	if len(got) != 2 || got[0] != entries[1] || got[1] != entries[2] {
+		t.Errorf("Decoded %v, want the frames after the corrupted one", got)
+	}
+	if len(lines) != 1 || lines[0] != "done" {
+		t.Errorf("Wrong text lines %q", lines)
+	}
+}
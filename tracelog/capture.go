@@ -0,0 +1,241 @@
+package tracelog
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/9elements/autorev/config"
+)
+
+// Capture - Filters parsed lines by the capture windows given in config
+type Capture struct {
+	// start and stop signals, any unfinished window starts on its start signal
+	// regardless of the order they are configured in
+	windows []config.Window
+	// true if windows are configured
+	checkCaptureState bool
+	// index into windows of the window currently capturing, -1 if none
+	active int
+	// windows whose stop signal has been seen
+	finished []bool
+	// all windows are finished
+	done bool
+	// captured entries
+	entries []TraceLogEntry
+	verbose bool
+	// host time of the data passed next, see SetReceived
+	received time.Time
+	// receives the captured entries instead of entries if set
+	stream *Stream
+	// number of captured entries
+	count int
+}
+
+// Windows - Returns the capture windows from config. The start and stop
+// signal form a single window without name.
+func Windows(cfg config.Config) []config.Window {
+	windows := cfg.TraceLog.Windows
+	if len(cfg.TraceLog.StartSignal.Type) > 0 && len(cfg.TraceLog.StopSignal.Type) > 0 {
+		windows = append([]config.Window{{
+			Start: cfg.TraceLog.StartSignal,
+			Stop:  cfg.TraceLog.StopSignal,
+		}}, windows...)
+	}
+	return windows
+}
+
+// NewCapture - Creates a new capture. Without windows every entry is captured
+func NewCapture(cfg config.Config) *Capture {
+	var c Capture
+	c.windows = Windows(cfg)
+	c.finished = make([]bool, len(c.windows))
+	c.checkCaptureState = len(c.windows) > 0
+	c.active = -1
+	return &c
+}
+
+// SetVerbose - Set verbosity of capturing
+func (c *Capture) SetVerbose(v bool) {
+	c.verbose = v
+}
+
+// SetStream - Pass captured entries to the stream instead of keeping them
+func (c *Capture) SetStream(s *Stream) {
+	c.stream = s
+}
+
+// SetReceived - Set the host time the next line or entry was received
+// It's stored in entries that don't have a receive time yet.
+func (c *Capture) SetReceived(ts time.Time) {
+	c.received = ts
+}
+
+// MatchSignal - Returns true if the entry matches the signal
+func MatchSignal(s *config.Signal, tle *TraceLogEntry) bool {
+	if s.Type != "" && s.Type != "*" && ConvertToType(s.Type) != tle.Type {
+		return false
+	}
+	if s.Direction != "" && s.Direction != "*" && ConvertToDir(s.Direction) != tle.Inout {
+		return false
+	}
+	if s.DataWidth != 0 && s.DataWidth != tle.AccessSize {
+		return false
+	}
+
+	offsetMask := s.OffsetMask
+	if offsetMask == 0 {
+		offsetMask = ^uint(0)
+	}
+	if tle.Address&offsetMask != s.Offset&offsetMask {
+		return false
+	}
+
+	valueMask := s.ValueMask
+	if valueMask == 0 {
+		valueMask = ^uint64(0)
+	}
+	v := tle.Value & valueMask
+	if s.ValueMax != 0 {
+		return v >= s.ValueMin && v <= s.ValueMax
+	}
+	return v == s.Value&valueMask
+}
+
+// capturing - Returns true if inside a window
+func (c *Capture) capturing() bool {
+	return !c.checkCaptureState || c.active >= 0
+}
+
+// AddLine - Parses a line and captures it if inside a window
+// Returns true once all windows are finished
+func (c *Capture) AddLine(buffer string) bool {
+	if c.done {
+		return true
+	}
+
+	inputLog, err := ParseLine(buffer)
+	if err != nil {
+		if c.verbose {
+			log.Printf(">%s<\n", buffer)
+		}
+		if c.capturing() {
+			log.Printf("Error ! %v\n", err)
+			log.Printf("Line was >>%s<<\n", buffer)
+		}
+		return false
+	}
+
+	return c.AddEntry(inputLog)
+}
+
+// capture - Keep or stream a captured entry
+func (c *Capture) capture(tle *TraceLogEntry) {
+	c.count++
+	if c.stream != nil {
+		c.stream.Add(*tle)
+		return
+	}
+	c.entries = append(c.entries, *tle)
+}
+
+// AddEntry - Captures an already parsed entry if inside a window
+// Returns true once all windows are finished
+func (c *Capture) AddEntry(inputLog *TraceLogEntry) bool {
+	if c.done {
+		return true
+	}
+	if inputLog.Received.IsZero() {
+		inputLog.Received = c.received
+	}
+
+	if !c.checkCaptureState {
+		c.capture(inputLog)
+		log.Printf("%v\n", inputLog)
+		return false
+	}
+
+	if c.active >= 0 {
+		w := &c.windows[c.active]
+		inputLog.Window = w.Name
+		c.capture(inputLog)
+		log.Printf("%v\n", inputLog)
+		if MatchSignal(&w.Stop, inputLog) {
+			c.finished[c.active] = true
+			c.active = -1
+			c.done = true
+			for i := range c.finished {
+				if !c.finished[i] {
+					c.done = false
+				}
+			}
+			return c.done
+		}
+		return false
+	}
+
+	for i := range c.windows {
+		if c.finished[i] {
+			continue
+		}
+		if MatchSignal(&c.windows[i].Start, inputLog) {
+			c.active = i
+			if len(c.windows[i].Name) > 0 {
+				log.Printf("Window %s started\n", c.windows[i].Name)
+			}
+			log.Printf("%v\n", inputLog)
+			break
+		}
+	}
+	return false
+}
+
+// Started - Returns true if any window has been started or no window is configured
+func (c *Capture) Started() bool {
+	if c.capturing() {
+		return true
+	}
+	for i := range c.finished {
+		if c.finished[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// Check - Returns an error if no window has been started or a window hasn't been stopped
+func (c *Capture) Check() error {
+	if !c.Started() {
+		return fmt.Errorf("Start signal not found in log")
+	}
+	if c.active >= 0 {
+		return fmt.Errorf("Stop signal of window '%s' not found in log", c.windows[c.active].Name)
+	}
+	for i := range c.finished {
+		if !c.finished[i] {
+			log.Printf("Window '%s' not found in log\n", c.windows[i].Name)
+		}
+	}
+	return nil
+}
+
+// Entries - Returns all captured entries, nil if they were streamed
+func (c *Capture) Entries() []TraceLogEntry {
+	return c.entries
+}
+
+// Count - Returns the number of captured entries
+func (c *Capture) Count() int {
+	return c.count
+}
+
+// SelectWindow - Returns the entries captured in the named window
+func SelectWindow(tles []TraceLogEntry, name string) []TraceLogEntry {
+	var ret []TraceLogEntry
+	for i := range tles {
+		if tles[i].Window == name {
+			ret = append(ret, tles[i])
+		}
+	}
+	return ret
+}
@@ -0,0 +1,76 @@
+package tracelog
+
+import (
+	"testing"
+
+	"github.com/9elements/autorev/config"
+)
+
+func TestMatchSignal(t *testing.T) {
+	tle := TraceLogEntry{Type: int(IO), Inout: false, Address: 0x80, Value: 0x9812, AccessSize: 16}
+
+	tests := []struct {
+		name   string
+		signal config.Signal
+		want   bool
+	}{
+		{"exact", config.Signal{Type: "i", Offset: 0x80, Value: 0x9812, Direction: "O", DataWidth: 16}, true},
+		{"wrong value", config.Signal{Type: "i", Offset: 0x80, Value: 0x9813, Direction: "O", DataWidth: 16}, false},
+		{"wrong width", config.Signal{Type: "i", Offset: 0x80, Value: 0x9812, Direction: "O", DataWidth: 8}, false},
+		{"wildcards", config.Signal{Type: "*", Offset: 0x80, Value: 0x9812, Direction: "*"}, true},
+		{"wrong direction", config.Signal{Type: "i", Offset: 0x80, Value: 0x9812, Direction: "I"}, false},
+		{"value mask", config.Signal{Type: "i", Offset: 0x80, Value: 0x9800, ValueMask: 0xff00}, true},
+		{"offset mask", config.Signal{Type: "i", Offset: 0x84, OffsetMask: 0xf0, Value: 0x9812}, true},
+		{"range", config.Signal{Type: "i", Offset: 0x80, ValueMin: 0x9800, ValueMax: 0x98ff}, true},
+		{"out of range", config.Signal{Type: "i", Offset: 0x80, ValueMin: 0x9900, ValueMax: 0x99ff}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchSignal(&tt.signal, &tle); got != tt.want {
+				t.Errorf("MatchSignal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptureWindows(t *testing.T) {
+	var cfg config.Config
+	post := func(v uint64) config.Signal {
+		return config.Signal{Type: "i", Offset: 0x80, Value: v, ValueMask: 0xff00, Direction: "O"}
+	}
+	cfg.TraceLog.Windows = []config.Window{
+		{Name: "fspm", Start: post(0x9800), Stop: post(0x9b00)},
+		{Name: "fsps", Start: post(0xa000), Stop: post(0xa300)},
+	}
+
+	c := NewCapture(cfg)
+	lines := []string{
+		"#B! 00000001 i O 00000080 00009801 16",
+		"#B! 00000002 m I fed40000 00000000 32",
+		"#B! 00000003 i O 00000080 00009b02 16",
+		"#B! 00000004 m I fed40004 00000000 32",
+		"#B! 00000005 i O 00000080 0000a001 16",
+		"#B! 00000006 p O 00000048 00000030 8",
+		"#B! 00000007 i O 00000080 0000a302 16",
+	}
+	for i, l := range lines {
+		done := c.AddLine(l)
+		if done != (i == len(lines)-1) {
+			t.Fatalf("AddLine returned %v on line %d", done, i)
+		}
+	}
+	if err := c.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	tles := c.Entries()
+	if len(tles) != 4 {
+		t.Fatalf("Got %d entries, want 4", len(tles))
+	}
+	if len(SelectWindow(tles, "fspm")) != 2 || len(SelectWindow(tles, "fsps")) != 2 {
+		t.Errorf("Wrong window assignment %v", tles)
+	}
+	if tles[1].Window != "fspm" || tles[2].Window != "fsps" {
+		t.Errorf("Wrong window names %v", tles)
+	}
+}
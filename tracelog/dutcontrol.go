@@ -0,0 +1,198 @@
+package tracelog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultCommandTimeout - Seconds a DUT control command may run if not configured
+const defaultCommandTimeout = 60
+
+// commandWaitDelay - Time to wait for the output of background processes started by a
+// command, e.g. QEMU, after the command itself exited
+const commandWaitDelay = time.Second
+
+// CommandResult - Outcome of a DUT control command
+type CommandResult struct {
+	// One of init, start, stop, restart, or qemu for the process
+	// launched by the QMP controller
+	Name     string
+	Command  string
+	Started  time.Time
+	Duration time.Duration
+	// -1 if the command couldn't be started or was killed
+	ExitCode int
+	Stdout   []byte
+	Stderr   []byte
+	Error    string
+}
+
+// SplitCommand - Split a command line into arguments like a POSIX shell
+// Supports single and double quotes and backslash escapes, but no expansion.
+func SplitCommand(s string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(c)
+			}
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else if c == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]) {
+				i++
+				arg.WriteRune(runes[i])
+			} else {
+				arg.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("Trailing backslash in command '%s'", s)
+			}
+			i++
+			arg.WriteRune(runes[i])
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("Unterminated quote in command '%s'", s)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("Empty command")
+	}
+	return args, nil
+}
+
+// resolveCommand - Returns the absolute path of the executable and the directory to run it in
+// Commands containing a slash are relative to the working directory and run in their own
+// directory, others are looked up in PATH and run in the working directory.
+func resolveCommand(name string) (string, string, error) {
+	if !strings.Contains(name, "/") {
+		path, err := exec.LookPath(name)
+		return path, "", err
+	}
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", "", err
+	}
+	return abs, filepath.Dir(abs), nil
+}
+
+// SetTestID - Set the test passed to DUT control commands in AUTOREV_TEST_ID
+func (tl *TraceLog) SetTestID(id int) {
+	tl.testID = id
+}
+
+// Commands - Returns the DUT control commands run by the last CollectNewTracelog
+// The commands of the first one include the init command run by CreateTraceLog.
+func (tl *TraceLog) Commands() []CommandResult {
+	return tl.commands
+}
+
+// commandTimeout - Seconds a DUT control command may take
+func (tl *TraceLog) commandTimeout() uint {
+	if tl.cfg.TraceLog.DutControl.Timeout == 0 {
+		return defaultCommandTimeout
+	}
+	return tl.cfg.TraceLog.DutControl.Timeout
+}
+
+// commandEnv - Environment passed to DUT control commands
+func (tl *TraceLog) commandEnv() []string {
+	env := os.Environ()
+	env = append(env, "AUTOREV_TEST_ID="+strconv.Itoa(tl.testID))
+	if len(tl.configPath) > 0 {
+		env = append(env, "AUTOREV_CONFIG="+tl.configPath)
+	}
+	return env
+}
+
+// runCommand - Run a DUT control command with timeout and record its output
+func (tl *TraceLog) runCommand(name string, command string) error {
+	res := CommandResult{Name: name, Command: command, Started: time.Now(), ExitCode: -1}
+	err := tl.execCommand(&res)
+	res.Duration = time.Since(res.Started)
+	if err != nil {
+		err = fmt.Errorf("Command %s '%s' failed: %v", name, command, err)
+		res.Error = err.Error()
+		log.Printf("%v\n", err)
+		if len(res.Stderr) > 0 {
+			log.Printf("%s\n", bytes.TrimSpace(res.Stderr))
+		}
+	}
+	tl.commands = append(tl.commands, res)
+	return err
+}
+
+func (tl *TraceLog) execCommand(res *CommandResult) error {
+	args, err := SplitCommand(res.Command)
+	if err != nil {
+		return err
+	}
+	path, dir, err := resolveCommand(args[0])
+	if err != nil {
+		return err
+	}
+
+	timeout := tl.commandTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	log.Printf("Running shell cmd '%s'\n", path)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args[1:]...)
+	cmd.Dir = dir
+	cmd.Env = tl.commandEnv()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = commandWaitDelay
+
+	err = cmd.Run()
+	res.Stdout = stdout.Bytes()
+	res.Stderr = stderr.Bytes()
+	if cmd.ProcessState != nil && cmd.ProcessState.Exited() {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("Timeout after %d seconds", timeout)
+	}
+	// Background processes keeping stdout open are expected
+	if errors.Is(err, exec.ErrWaitDelay) {
+		return nil
+	}
+	return err
+}
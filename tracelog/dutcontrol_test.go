@@ -0,0 +1,123 @@
+package tracelog
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		args []string
+	}{
+		{"qemu_test/start.sh", []string{"qemu_test/start.sh"}},
+		{"  ipmitool  -H bmc power on ", []string{"ipmitool", "-H", "bmc", "power", "on"}},
+		{`power.sh "my dut" 'a "b"' c\ d`, []string{"power.sh", "my dut", `a "b"`, "c d"}},
+		{`echo "\"\$x\n" ''`, []string{"echo", `"$x\n`, ""}},
+	}
+	for _, tt := range tests {
+		args, err := SplitCommand(tt.cmd)
+		if err != nil {
+			t.Errorf("%s: %v", tt.cmd, err)
+			continue
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: got %q, want %q", tt.cmd, args, tt.args)
+		}
+	}
+
+	for _, cmd := range []string{"", "  ", `echo "foo`, `echo 'foo`, `echo \`} {
+		if _, err := SplitCommand(cmd); err == nil {
+			t.Errorf("%s: expected error", cmd)
+		}
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	tl := &TraceLog{cfg: testConfig()}
+	tl.SetTestID(42)
+	tl.configPath = "/tmp/blob.bin"
+
+	err := tl.runCommand("start", `sh -c 'echo "$AUTOREV_TEST_ID $AUTOREV_CONFIG"; echo oops >&2'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tl.runCommand("stop", "sh -c 'exit 3'")
+	if err == nil {
+		t.Errorf("Expected error for failing command")
+	}
+	tl.cfg.TraceLog.DutControl.Timeout = 1
+	err = tl.runCommand("restart", "sleep 10")
+	if err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Errorf("Expected timeout, got %v", err)
+	}
+
+	cmds := tl.Commands()
+	if len(cmds) != 3 {
+		t.Fatalf("Got %d commands, want 3", len(cmds))
+	}
+	if string(cmds[0].Stdout) != "42 /tmp/blob.bin\n" || string(cmds[0].Stderr) != "oops\n" || cmds[0].ExitCode != 0 {
+		t.Errorf("Wrong result %+v", cmds[0])
+	}
+	if cmds[1].ExitCode != 3 || len(cmds[1].Error) == 0 {
+		t.Errorf("Wrong result %+v", cmds[1])
+	}
+	if cmds[2].ExitCode != -1 {
+		t.Errorf("Killed command has exit code %d", cmds[2].ExitCode)
+	}
+}
+
+func TestCollectPassesConfigToCommands(t *testing.T) {
+	d, tr := newFakeDUT([]string{
+		"#B! 000f0001 i O 00000080 0000ddaa 16",
+		"#B! 000f0004 i O 00000080 0000aadd 16",
+	})
+
+	dir := t.TempDir()
+	cfg := testConfig()
+	cfg.TraceLog.DutControl.StartCmd = "sh -c 'cp \"$AUTOREV_CONFIG\" " + dir + "/blob'"
+	cfg.TraceLog.DutControl.StopCmd = "false"
+	cfg.TraceLog.DutControl.InitCmd = "true"
+	tl, err := CreateTraceLog("", 0, "", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tl.SetTransport(tr)
+
+	blob := []byte{1, 2, 3}
+	_, err = tl.CollectNewTracelog(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-d.done
+
+	got, err := os.ReadFile(dir + "/blob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(blob) {
+		t.Errorf("Start command got config %x, want %x", got, blob)
+	}
+	cmds := tl.Commands()
+	if len(cmds) != 3 || cmds[0].Name != "init" || cmds[2].Name != "stop" || len(cmds[2].Error) == 0 {
+		t.Errorf("Wrong commands %+v", cmds)
+	}
+
+	// The init command belongs to the first test only
+	d, tr = newFakeDUT([]string{
+		"#B! 000f0001 i O 00000080 0000ddaa 16",
+		"#B! 000f0004 i O 00000080 0000aadd 16",
+	})
+	tl.SetTransport(tr)
+	_, err = tl.CollectNewTracelog(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-d.done
+	cmds = tl.Commands()
+	if len(cmds) != 2 || cmds[0].Name != "start" {
+		t.Errorf("Wrong commands of second test %+v", cmds)
+	}
+}
@@ -0,0 +1,110 @@
+package tracelog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DutController - Powers the DUT on and off
+type DutController interface {
+	// Init - Prepares DUT control, called once when creating the TraceLog
+	Init() error
+	// Start - Powers on the DUT. It may be held in reset until Resume is called
+	Start() error
+	// Restart - Resets the DUT after a failed attempt. It may be held in reset until Resume is called
+	Restart() error
+	// Resume - Lets the DUT run, called once the serial link is open
+	Resume() error
+	// Stop - Powers off the DUT
+	Stop() error
+	// Serial - Returns serial type and port of the DUT, empty if unknown
+	Serial() (string, string, error)
+}
+
+// DutControllerFactory - Creates a new DutController for the TraceLog
+// The TraceLog is used to access config and to record the commands run.
+type DutControllerFactory func(tl *TraceLog) (DutController, error)
+
+var dutControllers = map[string]DutControllerFactory{}
+
+// RegisterDutController - Makes a DUT controller available as dutcontrol type name
+func RegisterDutController(name string, factory DutControllerFactory) {
+	dutControllers[name] = factory
+}
+
+// NewDutController - Creates a new DutController for the dutcontrol type given in config
+// Defaults to shell.
+func NewDutController(tl *TraceLog) (DutController, error) {
+	name := tl.cfg.TraceLog.DutControl.Type
+	if name == "" {
+		name = "shell"
+	}
+	factory, ok := dutControllers[name]
+	if !ok {
+		return nil, fmt.Errorf("DUT control type '%s' is unknown (must be one of '%s')",
+			name, strings.Join(DutControllerNames(), "', '"))
+	}
+	return factory(tl)
+}
+
+// DutControllerNames - Returns the sorted names of all registered DUT controllers
+func DutControllerNames() []string {
+	var names []string
+	for k := range dutControllers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// shellController - Runs the scripts given in config
+type shellController struct {
+	tl *TraceLog
+}
+
+func init() {
+	RegisterDutController("shell", newShellController)
+}
+
+func newShellController(tl *TraceLog) (DutController, error) {
+	return &shellController{tl: tl}, nil
+}
+
+// run - Run the command if configured
+func (c *shellController) run(name string, command string) error {
+	if len(command) == 0 {
+		return nil
+	}
+	return c.tl.runCommand(name, command)
+}
+
+func (c *shellController) Init() error {
+	return c.run("init", c.tl.cfg.TraceLog.DutControl.InitCmd)
+}
+
+func (c *shellController) Start() error {
+	return c.run("start", c.tl.cfg.TraceLog.DutControl.StartCmd)
+}
+
+// Restart - Without restart command the DUT is stopped and started again
+func (c *shellController) Restart() error {
+	if len(c.tl.cfg.TraceLog.DutControl.RestartCmd) > 0 {
+		return c.run("restart", c.tl.cfg.TraceLog.DutControl.RestartCmd)
+	}
+	// A DUT that doesn't power off is reported, starting it might still work
+	c.Stop()
+	return c.Start()
+}
+
+func (c *shellController) Resume() error {
+	return nil
+}
+
+func (c *shellController) Stop() error {
+	return c.run("stop", c.tl.cfg.TraceLog.DutControl.StopCmd)
+}
+
+func (c *shellController) Serial() (string, string, error) {
+	return "", "", nil
+}
@@ -0,0 +1,67 @@
+package tracelog
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/9elements/autorev/config"
+)
+
+// FakeRule - Makes the DUT replace a value at IP and Address (BL_FAKE)
+type FakeRule struct {
+	// Instruction pointer
+	IP uint
+	// The address that is accessed
+	Address uint
+	// The value to return instead
+	Value uint64
+	// True: In, False: Out
+	Inout bool
+	// Use one of LineType
+	Type int
+}
+
+// FakeRulesFromConfig - Convert the fake rules from config.yml
+func FakeRulesFromConfig(rules []config.FakeRule) ([]FakeRule, error) {
+	var ret []FakeRule
+	for i, r := range rules {
+		t := ConvertToType(r.Type)
+		if t == -1 {
+			return nil, fmt.Errorf("Fake rule %d has unknown type '%s'", i, r.Type)
+		}
+		if r.Direction != "I" && r.Direction != "O" && r.Direction != "" {
+			return nil, fmt.Errorf("Fake rule %d has unknown direction '%s'", i, r.Direction)
+		}
+		// BL_FAKE only has 32 bit fields, a wider address would fake another access
+		if uint64(r.IP) > math.MaxUint32 || uint64(r.Address) > math.MaxUint32 {
+			return nil, fmt.Errorf("Fake rule %d doesn't fit in 32 bit IP and address", i)
+		}
+		ret = append(ret, FakeRule{
+			IP:      r.IP,
+			Address: r.Address,
+			Value:   r.Value,
+			// Only faking reads is specified
+			Inout: r.Direction != "O",
+			Type:  t,
+		})
+	}
+	return ret, nil
+}
+
+// String - Convert FakeRule into a Readable String
+func (f *FakeRule) String() string {
+	tle := TraceLogEntry{IP: f.IP, Type: f.Type, Inout: f.Inout, Address: f.Address, Value: f.Value}
+	return tle.String()
+}
+
+// payload - Encode as BL_FAKE payload
+func (f *FakeRule) payload() (string, error) {
+	if uint64(f.IP) > math.MaxUint32 || uint64(f.Address) > math.MaxUint32 {
+		return "", fmt.Errorf("Fake rule %s doesn't fit in 32 bit IP and address", f.String())
+	}
+	var inout int
+	if f.Inout {
+		inout = 1
+	}
+	return fmt.Sprintf("%08x%08x%016x%02x%02x", f.IP, f.Address, f.Value, inout, f.Type), nil
+}
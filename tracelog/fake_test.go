@@ -0,0 +1,28 @@
+package tracelog
+
+import (
+	"testing"
+
+	"github.com/9elements/autorev/config"
+)
+
+func TestFakeRulesFromConfig(t *testing.T) {
+	rules, err := FakeRulesFromConfig([]config.FakeRule{{IP: 0xf1234, Address: 0x64, Value: 0x80, Type: "i"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := rules[0].payload()
+	if err != nil || payload != "000f12340000006400000000000000800101" {
+		t.Errorf("Wrong payload %s, %v", payload, err)
+	}
+
+	// MEM64 above 4 GiB doesn't fit in BL_FAKE
+	_, err = FakeRulesFromConfig([]config.FakeRule{{IP: 0xf1234, Address: 0x100000000, Type: "M"}})
+	if err == nil {
+		t.Errorf("Fake rule above 4 GiB accepted")
+	}
+	wide := FakeRule{IP: 0x100000000, Address: 0x64, Type: int(IO)}
+	if _, err := wide.payload(); err == nil {
+		t.Errorf("Fake rule above 4 GiB encoded")
+	}
+}
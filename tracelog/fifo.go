@@ -0,0 +1,82 @@
+package tracelog
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/9elements/autorev/config"
+)
+
+// fifoTransport - A pair of named pipes, as used by qemu's "-serial pipe:"
+// Port + ".in" is written to, Port + ".out" is read from.
+type fifoTransport struct {
+	port    string
+	in, out *os.File
+}
+
+func init() {
+	RegisterTransport("fifo", newFIFOTransport)
+}
+
+func newFIFOTransport(cfg config.Config) (Transport, error) {
+	return &fifoTransport{port: cfg.TraceLog.Serial.Port}, nil
+}
+
+// Open - Opening a FIFO blocks until the other end is opened, too
+func (t *fifoTransport) Open(timeout uint) error {
+	limit := time.Duration(timeout) * time.Second
+
+	c1 := make(chan error, 1)
+	go func() {
+		in, err := os.OpenFile(t.port+".in", os.O_WRONLY, 0)
+		if err != nil {
+			c1 <- err
+			return
+		}
+		out, err := os.OpenFile(t.port+".out", os.O_RDONLY, 0)
+		if err != nil {
+			in.Close()
+			c1 <- err
+			return
+		}
+		t.in, t.out = in, out
+		c1 <- nil
+	}()
+	log.Printf("Waiting for open file\n")
+
+	select {
+	case err := <-c1:
+		if err != nil {
+			return err
+		}
+		if t.in == nil || t.out == nil {
+			return fmt.Errorf("Failed to open FIFO")
+		}
+		return nil
+	case <-time.After(limit):
+	}
+
+	return fmt.Errorf("Timeout waiting for fifo device")
+}
+
+func (t *fifoTransport) Read(b []byte) (int, error) {
+	return t.out.Read(b)
+}
+
+func (t *fifoTransport) Write(b []byte) (int, error) {
+	return t.in.Write(b)
+}
+
+func (t *fifoTransport) Close() error {
+	if t.in != nil {
+		t.in.Close()
+		t.in = nil
+	}
+	if t.out != nil {
+		t.out.Close()
+		t.out = nil
+	}
+	return nil
+}
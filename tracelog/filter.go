@@ -0,0 +1,73 @@
+package tracelog
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/9elements/autorev/config"
+)
+
+// Filter - Makes the DUT suppress all accesses of Type to Address (BL_FILTER)
+type Filter struct {
+	// The address that is accessed
+	Address uint
+	// Use one of LineType
+	Type int
+}
+
+// FiltersFromConfig - Convert the filters from config.yml
+func FiltersFromConfig(filters []config.Filter) ([]Filter, error) {
+	var ret []Filter
+	for i, f := range filters {
+		t := ConvertToType(f.Type)
+		if t == -1 {
+			return nil, fmt.Errorf("Filter %d has unknown type '%s'", i, f.Type)
+		}
+		// BL_FILTER only has a 32 bit address, a wider one would filter another access
+		if uint64(f.Address) > math.MaxUint32 {
+			return nil, fmt.Errorf("Filter %d doesn't fit in 32 bit address", i)
+		}
+		ret = append(ret, Filter{Address: f.Address, Type: t})
+	}
+	return ret, nil
+}
+
+// Matches - Returns true if the entry is suppressed by the filter
+func (f *Filter) Matches(tle *TraceLogEntry) bool {
+	return tle.Type == f.Type && tle.Address == f.Address
+}
+
+// String - Convert Filter into a Readable String
+func (f *Filter) String() string {
+	return fmt.Sprintf("%s %08x", ConvertFromType(f.Type), f.Address)
+}
+
+// payload - Encode as BL_FILTER payload
+func (f *Filter) payload() (string, error) {
+	if uint64(f.Address) > math.MaxUint32 {
+		return "", fmt.Errorf("Filter %s doesn't fit in 32 bit address", f.String())
+	}
+	return fmt.Sprintf("%08x%02x", f.Address, f.Type), nil
+}
+
+// ApplyFilters - Remove all entries matching one of the filters.
+// Used to make traces recorded with different filter sets comparable.
+func ApplyFilters(tles []TraceLogEntry, filters []Filter) []TraceLogEntry {
+	if len(filters) == 0 {
+		return tles
+	}
+	var ret []TraceLogEntry
+	for i := range tles {
+		filtered := false
+		for j := range filters {
+			if filters[j].Matches(&tles[i]) {
+				filtered = true
+				break
+			}
+		}
+		if !filtered {
+			ret = append(ret, tles[i])
+		}
+	}
+	return ret
+}
@@ -0,0 +1,39 @@
+package tracelog
+
+import (
+	"testing"
+
+	"github.com/9elements/autorev/config"
+)
+
+func TestApplyFilters(t *testing.T) {
+	tles := []TraceLogEntry{
+		{Type: int(IO), Address: 0x80, Value: 1},
+		{Type: int(IO), Address: 0x3f8, Value: 2},
+		{Type: int(MEM32), Address: 0x80, Value: 3},
+		{Type: int(IO), Address: 0x80, Value: 4},
+	}
+	filters := []Filter{{Address: 0x80, Type: int(IO)}}
+
+	ret := ApplyFilters(tles, filters)
+	if len(ret) != 2 || ret[0].Value != 2 || ret[1].Value != 3 {
+		t.Errorf("Wrong entries after filtering %v", ret)
+	}
+}
+
+func TestFiltersFromConfig(t *testing.T) {
+	filters, err := FiltersFromConfig([]config.Filter{{Type: "i", Address: 0x80}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := filters[0].payload()
+	if err != nil || payload != "0000008001" {
+		t.Errorf("Wrong payload %s, %v", payload, err)
+	}
+
+	// MEM64 above 4 GiB doesn't fit in BL_FILTER
+	_, err = FiltersFromConfig([]config.Filter{{Type: "M", Address: 0x100000000}})
+	if err == nil {
+		t.Errorf("Filter above 4 GiB accepted")
+	}
+}
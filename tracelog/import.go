@@ -0,0 +1,54 @@
+package tracelog
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/9elements/autorev/config"
+)
+
+// ImportTracelog - Parses a raw console capture, e.g. from minicom, using the
+// same capture windows as CollectNewTracelog
+func ImportTracelog(r io.Reader, cfg config.Config, verbose bool) ([]TraceLogEntry, error) {
+	capture := NewCapture(cfg)
+	capture.SetVerbose(verbose)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// Console captures might have timestamps or garbage in front of the prefix
+		if i := strings.Index(line, "#B!"); i > 0 {
+			// Logs dumped with -dumplog carry the host receive time
+			if ts, err := time.ParseInLocation(sessionLogTimeFormat, strings.Trim(line[:i], "[] "), time.Local); err == nil {
+				capture.SetReceived(ts)
+			}
+			line = line[i:]
+		}
+		if capture.AddLine(line) {
+			return capture.Entries(), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := capture.Check(); err != nil {
+		return nil, err
+	}
+	return capture.Entries(), nil
+}
+
+// ImportTracelogFile - See ImportTracelog
+func ImportTracelogFile(filename string, cfg config.Config, verbose bool) ([]TraceLogEntry, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ImportTracelog(f, cfg, verbose)
+}
@@ -0,0 +1,56 @@
+package tracelog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportTracelog(t *testing.T) {
+	capture := strings.Join([]string{
+		"Welcome to minicom 2.7.1",
+		"#B! 000f0000 i I 00000064 00000001 8",
+		"[12:00:01] #B! 000f0001 i O 00000080 0000ddaa 16\r",
+		"#B! 000f0002 m I fed40000 00000000 32",
+		"#B! 000f0003 i O 00000080 0000aadd 16",
+		"#B! 000f0004 i O 00000080 00000000 8",
+	}, "\n")
+
+	tles, err := ImportTracelog(strings.NewReader(capture), testConfig(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tles) != 2 {
+		t.Fatalf("Got %d entries, want 2", len(tles))
+	}
+	if tles[0].Address != 0xfed40000 || tles[0].Type != int(MEM32) {
+		t.Errorf("Wrong first entry %s", tles[0].String())
+	}
+}
+
+func TestImportTracelogNoStopSignal(t *testing.T) {
+	capture := "#B! 000f0001 i O 00000080 0000ddaa 16\n#B! 000f0002 m I fed40000 00000000 32\n"
+
+	if _, err := ImportTracelog(strings.NewReader(capture), testConfig(), false); err == nil {
+		t.Errorf("Expected error on missing stop signal")
+	}
+}
+
+func TestImportDumpedLog(t *testing.T) {
+	capture := strings.Join([]string{
+		"[2024-05-02 10:00:00.000000] #B! 000f0001 i O 00000080 0000ddaa 16",
+		"[2024-05-02 10:00:00.250000] #B! 000f0002 m I fed40000 00000000 32",
+		"[2024-05-02 10:00:01.500000] #B! 000f0003 i O 00000080 0000aadd 16",
+	}, "\n")
+
+	tles, err := ImportTracelog(strings.NewReader(capture), testConfig(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tles) != 2 {
+		t.Fatalf("Got %d entries, want 2", len(tles))
+	}
+	if d := tles[1].Received.Sub(tles[0].Received); d != 1250*time.Millisecond {
+		t.Errorf("Got %v between entries, want 1.25s", d)
+	}
+}
@@ -0,0 +1,145 @@
+package tracelog
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/9elements/autorev/ipmi"
+)
+
+const (
+	// ipmiPollInterval - Time between chassis status requests while waiting for a power change
+	ipmiPollInterval = 500 * time.Millisecond
+	// ipmiTimeout - Time to wait for a reply before retrying a request
+	ipmiTimeout = 2 * time.Second
+)
+
+// ipmiController - Powers the DUT with IPMI chassis commands over RMCP+
+// The init command is run as shell command, e.g. to flash the firmware.
+type ipmiController struct {
+	tl       *TraceLog
+	host     string
+	username string
+	password string
+}
+
+func init() {
+	RegisterDutController("ipmi", newIPMIController)
+}
+
+func newIPMIController(tl *TraceLog) (DutController, error) {
+	cfg := tl.cfg.TraceLog.DutControl.Ipmi
+	c := &ipmiController{
+		tl:       tl,
+		host:     cfg.Host,
+		username: cfg.Username,
+		password: cfg.Password,
+	}
+	if len(c.username) == 0 {
+		c.username = os.Getenv("AUTOREV_IPMI_USERNAME")
+	}
+	if len(c.password) == 0 {
+		c.password = os.Getenv("AUTOREV_IPMI_PASSWORD")
+	}
+	if len(c.host) == 0 {
+		return nil, fmt.Errorf("DUT control type ipmi needs a host")
+	}
+	return c, nil
+}
+
+func (c *ipmiController) Init() error {
+	if len(c.tl.cfg.TraceLog.DutControl.InitCmd) == 0 {
+		return nil
+	}
+	return c.tl.runCommand("init", c.tl.cfg.TraceLog.DutControl.InitCmd)
+}
+
+// Start - Power the DUT off and on again, so it always boots from reset
+func (c *ipmiController) Start() error {
+	err := c.power("start", false)
+	if err != nil {
+		return err
+	}
+	return c.power("start", true)
+}
+
+func (c *ipmiController) Restart() error {
+	err := c.power("restart", false)
+	if err != nil {
+		return err
+	}
+	return c.power("restart", true)
+}
+
+func (c *ipmiController) Resume() error {
+	return nil
+}
+
+func (c *ipmiController) Stop() error {
+	return c.power("stop", false)
+}
+
+func (c *ipmiController) Serial() (string, string, error) {
+	return "", "", nil
+}
+
+// power - Switch chassis power and record it as command
+func (c *ipmiController) power(name string, on bool) error {
+	action := ipmi.PowerOff
+	if on {
+		action = ipmi.PowerOn
+	}
+	res := CommandResult{Name: name, Command: "chassis power " + action.String(), Started: time.Now(), ExitCode: -1}
+
+	log.Printf("IPMI chassis power %s\n", action)
+	err := c.setPower(action)
+	res.Duration = time.Since(res.Started)
+	if err != nil {
+		err = fmt.Errorf("IPMI chassis power %s failed: %v", action, err)
+		res.Error = err.Error()
+		log.Printf("%v\n", err)
+	} else {
+		res.ExitCode = 0
+		res.Stdout = []byte(fmt.Sprintf("Chassis Power is %s\n", action))
+	}
+	c.tl.commands = append(c.tl.commands, res)
+	return err
+}
+
+// setPower - Send the power action if needed and poll until the chassis reached it
+func (c *ipmiController) setPower(action ipmi.PowerAction) error {
+	client, err := ipmi.Dial(c.host, c.username, c.password, ipmiTimeout)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	want := action == ipmi.PowerOn
+	on, err := client.PowerStatus()
+	if err != nil {
+		return err
+	}
+	if on == want {
+		return nil
+	}
+	err = client.ChassisControl(action)
+	if err != nil {
+		return err
+	}
+
+	limit := time.Duration(c.tl.commandTimeout()) * time.Second
+	n := time.Now()
+	for time.Since(n) < limit {
+		time.Sleep(ipmiPollInterval)
+		on, err = client.PowerStatus()
+		if err != nil {
+			return err
+		}
+		if on == want {
+			return nil
+		}
+	}
+	return fmt.Errorf("Timeout waiting for chassis power %s", action)
+}
@@ -0,0 +1,84 @@
+package tracelog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/9elements/autorev/ipmi"
+)
+
+func TestIPMIController(t *testing.T) {
+	bmc, err := ipmi.NewFakeBMC("127.0.0.1:0", "admin", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bmc.Close()
+	bmc.SetPower(true)
+	bmc.SetPowerDelay(100 * time.Millisecond)
+
+	t.Setenv("AUTOREV_IPMI_PASSWORD", "secret")
+	cfg := testConfig()
+	cfg.TraceLog.DutControl.Type = "ipmi"
+	cfg.TraceLog.DutControl.Ipmi.Host = bmc.Addr()
+	cfg.TraceLog.DutControl.Ipmi.Username = "admin"
+	cfg.TraceLog.DutControl.Timeout = 5
+	tl := &TraceLog{cfg: cfg}
+	dut, err := NewDutController(tl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dut.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if !bmc.Power() {
+		t.Errorf("DUT not powered on")
+	}
+	if err := dut.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if bmc.Power() {
+		t.Errorf("DUT not powered off")
+	}
+	// Already off, nothing to do
+	if err := dut.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ipmi.PowerAction{ipmi.PowerOff, ipmi.PowerOn, ipmi.PowerOff}
+	if !reflect.DeepEqual(bmc.Actions(), want) {
+		t.Errorf("BMC got %v, want %v", bmc.Actions(), want)
+	}
+	if bmc.Sessions() != 0 {
+		t.Errorf("%d sessions left open", bmc.Sessions())
+	}
+	if len(tl.Commands()) != 4 || tl.Commands()[3].Command != "chassis power off" {
+		t.Errorf("Wrong commands recorded %+v", tl.Commands())
+	}
+}
+
+func TestIPMIControllerWrongPassword(t *testing.T) {
+	bmc, err := ipmi.NewFakeBMC("127.0.0.1:0", "admin", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bmc.Close()
+
+	cfg := testConfig()
+	cfg.TraceLog.DutControl.Type = "ipmi"
+	cfg.TraceLog.DutControl.Ipmi.Host = bmc.Addr()
+	cfg.TraceLog.DutControl.Ipmi.Username = "admin"
+	cfg.TraceLog.DutControl.Ipmi.Password = "wrong"
+	tl := &TraceLog{cfg: cfg}
+	dut, err := NewDutController(tl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dut.Start(); err == nil {
+		t.Errorf("Expected error for wrong password")
+	}
+	if len(bmc.Actions()) != 0 {
+		t.Errorf("BMC executed %v", bmc.Actions())
+	}
+}
@@ -0,0 +1,332 @@
+package tracelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// qmpController - Controls QEMU over the QEMU Machine Protocol
+// If a binary is given QEMU is launched paused with a QMP socket, otherwise an already
+// running QEMU listening on the socket is used and only reset.
+type qmpController struct {
+	tl     *TraceLog
+	socket string
+
+	// launched QEMU, nil if not running or not launched by us
+	qemu       *exec.Cmd
+	qemuDone   chan error
+	qemuOutput bytes.Buffer
+	qemuResult CommandResult
+
+	conn net.Conn
+	dec  *json.Decoder
+}
+
+// qmpResponse - A message received from QEMU
+type qmpResponse struct {
+	QMP    json.RawMessage `json:"QMP"`
+	Return json.RawMessage `json:"return"`
+	Error  *struct {
+		Class string `json:"class"`
+		Desc  string `json:"desc"`
+	} `json:"error"`
+	Event string `json:"event"`
+}
+
+// qmpChardev - An entry returned by query-chardev
+type qmpChardev struct {
+	Label    string `json:"label"`
+	Filename string `json:"filename"`
+}
+
+func init() {
+	RegisterDutController("qmp", newQMPController)
+}
+
+func newQMPController(tl *TraceLog) (DutController, error) {
+	qmp := tl.cfg.TraceLog.DutControl.Qmp
+	c := &qmpController{tl: tl, socket: qmp.Socket}
+	if len(c.socket) == 0 {
+		if len(qmp.Binary) == 0 {
+			return nil, fmt.Errorf("DUT control type qmp needs a binary or a socket")
+		}
+		c.socket = filepath.Join(os.TempDir(), fmt.Sprintf("autorev-qmp-%d.sock", os.Getpid()))
+	}
+	return c, nil
+}
+
+func (c *qmpController) Init() error {
+	return nil
+}
+
+// Start - Launch QEMU paused, or reset the running one
+func (c *qmpController) Start() error {
+	if len(c.tl.cfg.TraceLog.DutControl.Qmp.Binary) > 0 {
+		if c.qemu != nil {
+			c.Stop()
+		}
+		err := c.launch()
+		if err != nil {
+			return err
+		}
+	}
+	if c.conn == nil {
+		err := c.connect()
+		if err != nil {
+			return err
+		}
+	}
+	return c.reset("start")
+}
+
+// Restart - Reset QEMU and keep it paused until Resume
+func (c *qmpController) Restart() error {
+	if c.conn == nil {
+		return c.Start()
+	}
+	return c.reset("restart")
+}
+
+func (c *qmpController) reset(name string) error {
+	_, err := c.execute(name, "stop")
+	if err != nil {
+		return err
+	}
+	_, err = c.execute(name, "system_reset")
+	return err
+}
+
+func (c *qmpController) Resume() error {
+	_, err := c.execute("start", "cont")
+	return err
+}
+
+// Stop - Quit the launched QEMU, an external one is only paused
+func (c *qmpController) Stop() error {
+	var err error
+	if c.conn != nil {
+		command := "quit"
+		if c.qemu == nil {
+			command = "stop"
+		}
+		_, err = c.execute("stop", command)
+	} else if c.qemu != nil {
+		c.qemu.Process.Kill()
+	}
+	c.shutdown()
+	return err
+}
+
+// Serial - Discover the serial port from the chardev backing the guest's serial
+func (c *qmpController) Serial() (string, string, error) {
+	ret, err := c.execute("start", "query-chardev")
+	if err != nil {
+		return "", "", err
+	}
+	var chardevs []qmpChardev
+	err = json.Unmarshal(ret, &chardevs)
+	if err != nil {
+		return "", "", err
+	}
+
+	label := c.tl.cfg.TraceLog.DutControl.Qmp.Chardev
+	if len(label) == 0 {
+		label = "serial0"
+	}
+	for _, cd := range chardevs {
+		if cd.Label == label {
+			return parseChardev(cd.Filename)
+		}
+	}
+	return "", "", fmt.Errorf("QEMU has no chardev '%s'", label)
+}
+
+// parseChardev - Returns serial type and port for the filename of a QEMU chardev
+func parseChardev(filename string) (string, string, error) {
+	filename = strings.TrimPrefix(filename, "disconnected:")
+	switch {
+	case strings.HasPrefix(filename, "pty:"):
+		return "tty", filename[4:], nil
+	case strings.HasPrefix(filename, "pipe:"):
+		return "fifo", filename[5:], nil
+	case strings.HasPrefix(filename, "tcp:"):
+		opts := strings.Split(filename[4:], ",")
+		for _, o := range opts[1:] {
+			if o == "server" || o == "server=on" {
+				return "tcp", opts[0], nil
+			}
+		}
+		return "", "", fmt.Errorf("Chardev '%s' must be a TCP server", filename)
+	}
+	return "", "", fmt.Errorf("Chardev '%s' can't be used as serial", filename)
+}
+
+// launch - Start QEMU paused with a QMP socket
+func (c *qmpController) launch() error {
+	qmp := c.tl.cfg.TraceLog.DutControl.Qmp
+	path, dir, err := resolveCommand(qmp.Binary)
+	if err != nil {
+		return err
+	}
+	os.Remove(c.socket)
+
+	args := append([]string{}, qmp.Args...)
+	args = append(args, "-qmp", "unix:"+c.socket+",server=on,wait=off", "-S")
+
+	log.Printf("Launching '%s'\n", path)
+
+	c.qemuOutput.Reset()
+	c.qemu = exec.Command(path, args...)
+	c.qemu.Dir = dir
+	c.qemu.Env = c.tl.commandEnv()
+	c.qemu.Stdout = &c.qemuOutput
+	c.qemu.Stderr = &c.qemuOutput
+	c.qemuResult = CommandResult{
+		Name:     "qemu",
+		Command:  strings.Join(append([]string{path}, args...), " "),
+		Started:  time.Now(),
+		ExitCode: -1,
+	}
+
+	err = c.qemu.Start()
+	if err != nil {
+		c.qemu = nil
+		return err
+	}
+	c.qemuDone = make(chan error, 1)
+	go func(cmd *exec.Cmd, done chan error) {
+		done <- cmd.Wait()
+	}(c.qemu, c.qemuDone)
+	return nil
+}
+
+// shutdown - Close the QMP connection and wait for the launched QEMU to exit
+func (c *qmpController) shutdown() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	if c.qemu == nil {
+		return
+	}
+
+	var err error
+	select {
+	case err = <-c.qemuDone:
+	case <-time.After(time.Duration(c.tl.commandTimeout()) * time.Second):
+		c.qemu.Process.Kill()
+		err = <-c.qemuDone
+	}
+
+	res := c.qemuResult
+	res.Duration = time.Since(res.Started)
+	res.Stdout = append([]byte{}, c.qemuOutput.Bytes()...)
+	if c.qemu.ProcessState != nil && c.qemu.ProcessState.Exited() {
+		res.ExitCode = c.qemu.ProcessState.ExitCode()
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	c.tl.commands = append(c.tl.commands, res)
+	c.qemu = nil
+	os.Remove(c.socket)
+}
+
+// connect - Connect to the QMP socket and enter command mode
+func (c *qmpController) connect() error {
+	limit := time.Duration(c.tl.commandTimeout()) * time.Second
+	n := time.Now()
+
+	for {
+		conn, err := net.Dial("unix", c.socket)
+		if err == nil {
+			c.conn = conn
+			break
+		}
+		if time.Since(n) >= limit {
+			return fmt.Errorf("Timeout waiting for QMP socket %s: %v", c.socket, err)
+		}
+		if c.qemu != nil {
+			select {
+			case err := <-c.qemuDone:
+				c.qemuDone <- err
+				return fmt.Errorf("QEMU exited: %v %s", err, c.qemuOutput.String())
+			default:
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	c.dec = json.NewDecoder(c.conn)
+	var greeting qmpResponse
+	c.conn.SetReadDeadline(time.Now().Add(limit))
+	err := c.dec.Decode(&greeting)
+	if err != nil {
+		c.conn.Close()
+		c.conn = nil
+		return err
+	}
+	if greeting.QMP == nil {
+		c.conn.Close()
+		c.conn = nil
+		return fmt.Errorf("No QMP greeting on %s", c.socket)
+	}
+
+	_, err = c.execute("start", "qmp_capabilities")
+	return err
+}
+
+// execute - Run a QMP command and return its result
+func (c *qmpController) execute(name string, command string) (json.RawMessage, error) {
+	res := CommandResult{Name: name, Command: command, Started: time.Now(), ExitCode: -1}
+	ret, err := c.executeRaw(command)
+	res.Duration = time.Since(res.Started)
+	res.Stdout = ret
+	if err != nil {
+		err = fmt.Errorf("QMP command %s failed: %v", command, err)
+		res.Error = err.Error()
+		log.Printf("%v\n", err)
+	} else {
+		res.ExitCode = 0
+	}
+	c.tl.commands = append(c.tl.commands, res)
+	return ret, err
+}
+
+func (c *qmpController) executeRaw(command string) (json.RawMessage, error) {
+	if c.conn == nil {
+		return nil, fmt.Errorf("Not connected")
+	}
+	c.conn.SetDeadline(time.Now().Add(time.Duration(c.tl.commandTimeout()) * time.Second))
+
+	_, err := fmt.Fprintf(c.conn, "{\"execute\": \"%s\"}\n", command)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var resp qmpResponse
+		err = c.dec.Decode(&resp)
+		if err != nil {
+			// quit closes the connection, maybe before the reply
+			if command == "quit" {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if len(resp.Event) > 0 {
+			continue
+		}
+		if resp.Error != nil {
+			return nil, fmt.Errorf("%s: %s", resp.Error.Class, resp.Error.Desc)
+		}
+		return resp.Return, nil
+	}
+}
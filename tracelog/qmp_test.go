@@ -0,0 +1,158 @@
+package tracelog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fakeQMP - Accepts QMP connections and records the commands executed
+type fakeQMP struct {
+	l        net.Listener
+	commands chan string
+}
+
+func newFakeQMP(t *testing.T, chardev string) *fakeQMP {
+	l, err := net.Listen("unix", filepath.Join(t.TempDir(), "qmp.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := &fakeQMP{l: l, commands: make(chan string, 100)}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go q.serve(conn, chardev)
+		}
+	}()
+	return q
+}
+
+func (q *fakeQMP) serve(conn net.Conn, chardev string) {
+	defer conn.Close()
+	fmt.Fprintf(conn, "{\"QMP\": {\"version\": {}, \"capabilities\": []}}\r\n")
+
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var cmd struct {
+			Execute string `json:"execute"`
+		}
+		json.Unmarshal(line, &cmd)
+		q.commands <- cmd.Execute
+
+		switch cmd.Execute {
+		case "query-chardev":
+			fmt.Fprintf(conn, "{\"return\": [{\"frontend-open\": false, \"filename\": \"vc\", \"label\": \"parallel0\"},"+
+				" {\"frontend-open\": true, \"filename\": \"%s\", \"label\": \"serial0\"}]}\r\n", chardev)
+		case "system_reset":
+			fmt.Fprintf(conn, "{\"timestamp\": {}, \"event\": \"RESET\"}\r\n{\"return\": {}}\r\n")
+		case "bogus":
+			fmt.Fprintf(conn, "{\"error\": {\"class\": \"CommandNotFound\", \"desc\": \"The command bogus has not been found\"}}\r\n")
+		default:
+			fmt.Fprintf(conn, "{\"return\": {}}\r\n")
+		}
+	}
+}
+
+// received - Returns the commands executed so far
+func (q *fakeQMP) received() []string {
+	var ret []string
+	for {
+		select {
+		case c := <-q.commands:
+			ret = append(ret, c)
+		default:
+			return ret
+		}
+	}
+}
+
+func TestQMPController(t *testing.T) {
+	q := newFakeQMP(t, "disconnected:tcp:127.0.0.1:4555,server=on")
+	defer q.l.Close()
+
+	cfg := testConfig()
+	cfg.TraceLog.DutControl.Type = "qmp"
+	cfg.TraceLog.DutControl.Qmp.Socket = q.l.Addr().String()
+	tl := &TraceLog{cfg: cfg}
+	dut, err := NewDutController(tl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dut.Start(); err != nil {
+		t.Fatal(err)
+	}
+	serialType, port, err := dut.Serial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serialType != "tcp" || port != "127.0.0.1:4555" {
+		t.Errorf("Got serial %s %s", serialType, port)
+	}
+	if err := dut.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dut.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dut.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"qmp_capabilities", "stop", "system_reset", "query-chardev", "cont", "stop", "system_reset", "stop"}
+	if got := q.received(); !reflect.DeepEqual(got, want) {
+		t.Errorf("QEMU got %v, want %v", got, want)
+	}
+	if len(tl.Commands()) != len(want) {
+		t.Errorf("Recorded %d commands, want %d", len(tl.Commands()), len(want))
+	}
+
+	// Reconnects after stop
+	if err := dut.Start(); err != nil {
+		t.Fatal(err)
+	}
+	c := dut.(*qmpController)
+	if _, err := c.execute("start", "bogus"); err == nil {
+		t.Errorf("Expected error for unknown command")
+	}
+	dut.Stop()
+}
+
+func TestParseChardev(t *testing.T) {
+	tests := []struct {
+		filename   string
+		serialType string
+		port       string
+	}{
+		{"pty:/dev/pts/7", "tty", "/dev/pts/7"},
+		{"pipe:/tmp/guest", "fifo", "/tmp/guest"},
+		{"tcp:localhost:4555,server", "tcp", "localhost:4555"},
+		{"disconnected:tcp:127.0.0.1:4555,server=on", "tcp", "127.0.0.1:4555"},
+		{"tcp:127.0.0.1:4555", "", ""},
+		{"unix:/tmp/serial.sock,server=on", "", ""},
+		{"vc", "", ""},
+	}
+	for _, tt := range tests {
+		serialType, port, err := parseChardev(tt.filename)
+		if tt.serialType == "" {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.filename)
+			}
+			continue
+		}
+		if err != nil || serialType != tt.serialType || port != tt.port {
+			t.Errorf("%s: got %s %s %v", tt.filename, serialType, port, err)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package tracelog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/9elements/autorev/config"
+)
+
+// Telnet commands and options (RFC 854, RFC 856, RFC 858)
+const (
+	telnetSE   = 240
+	telnetSB   = 250
+	telnetWILL = 251
+	telnetWONT = 252
+	telnetDO   = 253
+	telnetDONT = 254
+	telnetIAC  = 255
+
+	telnetOptBinary  = 0
+	telnetOptSGA     = 3
+	telnetOptComPort = 44
+)
+
+// RFC 2217 COM-PORT-OPTION client to server commands.
+// The server answers with the command value + 100.
+const (
+	comPortSetBaudRate  = 1
+	comPortSetDataSize  = 2
+	comPortSetParity    = 3
+	comPortSetStopSize  = 4
+	comPortServerOffset = 100
+)
+
+// Telnet receive parser states
+const (
+	telnetStateData = iota
+	telnetStateIAC
+	telnetStateOption
+	telnetStateSB
+	telnetStateSBIAC
+)
+
+// rfc2217Transport - A remote serial port behind a network serial server
+// Line settings are configured by the Telnet COM-PORT-OPTION.
+type rfc2217Transport struct {
+	addr string
+	ls   lineSettings
+	conn net.Conn
+
+	// Guards the receive path, a Read left behind by a timed out read may
+	// still run when the next one starts
+	rxMutex sync.Mutex
+	// Data received while negotiating
+	pending []byte
+	// Telnet parser
+	state   int
+	command byte
+	sb      []byte
+	// COM-PORT-OPTION replies received from the server
+	mutex sync.Mutex
+	acks  map[byte][]byte
+}
+
+func init() {
+	RegisterTransport("rfc2217", newRFC2217Transport)
+}
+
+func newRFC2217Transport(cfg config.Config) (Transport, error) {
+	if _, _, err := net.SplitHostPort(cfg.TraceLog.Serial.Port); err != nil {
+		return nil, fmt.Errorf("Serial port must be 'host:port' for type rfc2217: %v", err)
+	}
+	ls, err := getLineSettings(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &rfc2217Transport{addr: cfg.TraceLog.Serial.Port, ls: ls}, nil
+}
+
+// Open - Connect to the serial server and configure the remote serial port
+func (t *rfc2217Transport) Open(timeout uint) error {
+	var err error
+	n := time.Now()
+	limit := time.Duration(timeout) * time.Second
+
+	for time.Since(n) < limit {
+		t.conn, err = net.DialTimeout("tcp", t.addr, limit-time.Since(n))
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if t.conn == nil {
+		return fmt.Errorf("Timeout connecting to %s: %v", t.addr, err)
+	}
+
+	t.rxMutex.Lock()
+	t.pending = nil
+	t.state = telnetStateData
+	t.rxMutex.Unlock()
+	t.mutex.Lock()
+	t.acks = map[byte][]byte{}
+	t.mutex.Unlock()
+
+	err = t.negotiate(limit - time.Since(n))
+	if err != nil {
+		t.Close()
+		return err
+	}
+	return nil
+}
+
+// negotiate - Enable binary mode and set the remote line settings
+func (t *rfc2217Transport) negotiate(limit time.Duration) error {
+	var parity, stopsize byte
+	switch t.ls.Parity {
+	case "none":
+		parity = 1
+	case "odd":
+		parity = 2
+	case "even":
+		parity = 3
+	case "mark":
+		parity = 4
+	case "space":
+		parity = 5
+	}
+	switch t.ls.StopBits {
+	case "1":
+		stopsize = 1
+	case "2":
+		stopsize = 2
+	case "1.5":
+		stopsize = 3
+	}
+	baud := make([]byte, 4)
+	binary.BigEndian.PutUint32(baud, uint32(t.ls.BaudRate))
+
+	req := []byte{
+		telnetIAC, telnetWILL, telnetOptComPort,
+		telnetIAC, telnetWILL, telnetOptBinary,
+		telnetIAC, telnetDO, telnetOptBinary,
+		telnetIAC, telnetDO, telnetOptSGA,
+	}
+	req = append(req, comPortCommand(comPortSetBaudRate, baud)...)
+	req = append(req, comPortCommand(comPortSetDataSize, []byte{byte(t.ls.DataBits)})...)
+	req = append(req, comPortCommand(comPortSetParity, []byte{parity})...)
+	req = append(req, comPortCommand(comPortSetStopSize, []byte{stopsize})...)
+	if _, err := t.conn.Write(req); err != nil {
+		return err
+	}
+
+	// Wait for the server to acknowledge all settings
+	t.conn.SetReadDeadline(time.Now().Add(limit))
+	defer t.conn.SetReadDeadline(time.Time{})
+
+	want := []byte{comPortSetBaudRate, comPortSetDataSize, comPortSetParity, comPortSetStopSize}
+	buf := make([]byte, 256)
+	for {
+		t.mutex.Lock()
+		missing := 0
+		for _, c := range want {
+			if _, ok := t.acks[c+comPortServerOffset]; !ok {
+				missing++
+			}
+		}
+		t.mutex.Unlock()
+		if missing == 0 {
+			break
+		}
+
+		t.rxMutex.Lock()
+		n, err := t.conn.Read(buf)
+		if err != nil {
+			t.rxMutex.Unlock()
+			return fmt.Errorf("RFC 2217 server didn't acknowledge line settings: %v", err)
+		}
+		t.pending = append(t.pending, t.decode(buf[:n])...)
+		t.rxMutex.Unlock()
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if b := t.acks[comPortSetBaudRate+comPortServerOffset]; len(b) == 4 &&
+		binary.BigEndian.Uint32(b) != uint32(t.ls.BaudRate) {
+		log.Printf("RFC 2217 server set baudrate %d instead of %d\n", binary.BigEndian.Uint32(b), t.ls.BaudRate)
+	}
+	return nil
+}
+
+// comPortCommand - Encode a COM-PORT-OPTION subnegotiation
+func comPortCommand(cmd byte, value []byte) []byte {
+	ret := []byte{telnetIAC, telnetSB, telnetOptComPort, cmd}
+	ret = append(ret, telnetEscape(value)...)
+	return append(ret, telnetIAC, telnetSE)
+}
+
+// telnetEscape - Double every IAC byte
+func telnetEscape(b []byte) []byte {
+	var ret []byte
+	for _, c := range b {
+		if c == telnetIAC {
+			ret = append(ret, telnetIAC)
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+// decode - Strips Telnet commands from the received stream and returns the data bytes
+// Must be called with rxMutex held.
+func (t *rfc2217Transport) decode(raw []byte) []byte {
+	var data []byte
+	for _, c := range raw {
+		switch t.state {
+		case telnetStateData:
+			if c == telnetIAC {
+				t.state = telnetStateIAC
+			} else {
+				data = append(data, c)
+			}
+		case telnetStateIAC:
+			switch c {
+			case telnetIAC:
+				data = append(data, c)
+				t.state = telnetStateData
+			case telnetWILL, telnetWONT, telnetDO, telnetDONT:
+				t.command = c
+				t.state = telnetStateOption
+			case telnetSB:
+				t.sb = nil
+				t.state = telnetStateSB
+			default:
+				// NOP, GA, BREAK, ...
+				t.state = telnetStateData
+			}
+		case telnetStateOption:
+			t.handleOption(t.command, c)
+			t.state = telnetStateData
+		case telnetStateSB:
+			if c == telnetIAC {
+				t.state = telnetStateSBIAC
+			} else {
+				t.sb = append(t.sb, c)
+			}
+		case telnetStateSBIAC:
+			if c == telnetSE {
+				t.handleSubnegotiation(t.sb)
+				t.state = telnetStateData
+			} else {
+				t.sb = append(t.sb, c)
+				t.state = telnetStateSB
+			}
+		}
+	}
+	return data
+}
+
+// handleOption - Refuse every option we didn't ask for
+func (t *rfc2217Transport) handleOption(cmd byte, opt byte) {
+	switch cmd {
+	case telnetDO:
+		if opt != telnetOptBinary && opt != telnetOptComPort {
+			t.conn.Write([]byte{telnetIAC, telnetWONT, opt})
+		}
+	case telnetWILL:
+		if opt != telnetOptBinary && opt != telnetOptSGA {
+			t.conn.Write([]byte{telnetIAC, telnetDONT, opt})
+		}
+	case telnetDONT:
+		if opt == telnetOptComPort {
+			log.Printf("Serial server refused RFC 2217 COM-PORT-OPTION\n")
+		}
+	}
+}
+
+// handleSubnegotiation - Record the server's COM-PORT-OPTION replies
+func (t *rfc2217Transport) handleSubnegotiation(sb []byte) {
+	if len(sb) < 2 || sb[0] != telnetOptComPort {
+		return
+	}
+	t.mutex.Lock()
+	t.acks[sb[1]] = append([]byte{}, sb[2:]...)
+	t.mutex.Unlock()
+}
+
+func (t *rfc2217Transport) Read(b []byte) (int, error) {
+	t.rxMutex.Lock()
+	defer t.rxMutex.Unlock()
+
+	buf := make([]byte, len(b))
+	for len(t.pending) == 0 {
+		n, err := t.conn.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+		t.pending = t.decode(buf[:n])
+	}
+	n := copy(b, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
+}
+
+func (t *rfc2217Transport) Write(b []byte) (int, error) {
+	_, err := t.conn.Write(telnetEscape(b))
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (t *rfc2217Transport) Close() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
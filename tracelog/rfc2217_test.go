@@ -0,0 +1,204 @@
+package tracelog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+)
+
+// fakeSerialServer - Minimal RFC 2217 stand-in server in front of a fakeDUT
+type fakeSerialServer struct {
+	listener net.Listener
+	baud     uint32
+	settings map[byte]byte
+	dut      *fakeDUT
+}
+
+func newFakeSerialServer(t *testing.T, trace []string) *fakeSerialServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSerialServer{listener: l, settings: map[byte]byte{}}
+	dutR, toDUT := io.Pipe()
+	fromDUT, dutW := io.Pipe()
+	s.dut = startFakeDUT(trace, dutR, dutW)
+
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		// DUT output: escape IAC and prefix with a Telnet NOP
+		go func() {
+			c.Write([]byte{telnetIAC, 241})
+			buf := make([]byte, 64)
+			for {
+				n, err := fromDUT.Read(buf)
+				if err != nil {
+					return
+				}
+				c.Write(telnetEscape(buf[:n]))
+			}
+		}()
+		s.serve(c, toDUT)
+	}()
+	return s
+}
+
+// serve - Decode the client stream, answer COM-PORT-OPTION commands and pass data to the DUT
+func (s *fakeSerialServer) serve(c net.Conn, toDUT io.WriteCloser) {
+	defer toDUT.Close()
+	buf := make([]byte, 1)
+	next := func() byte {
+		if _, err := io.ReadFull(c, buf); err != nil {
+			panic(err)
+		}
+		return buf[0]
+	}
+	defer func() { recover() }()
+
+	for {
+		b := next()
+		if b != telnetIAC {
+			toDUT.Write([]byte{b})
+			continue
+		}
+		switch cmd := next(); cmd {
+		case telnetIAC:
+			toDUT.Write([]byte{telnetIAC})
+		case telnetWILL, telnetDO:
+			opt := next()
+			reply := byte(telnetDO)
+			if cmd == telnetDO {
+				reply = telnetWILL
+			}
+			c.Write([]byte{telnetIAC, reply, opt})
+		case telnetSB:
+			var sb []byte
+			for {
+				b := next()
+				if b == telnetIAC {
+					if next() == telnetSE {
+						break
+					}
+				}
+				sb = append(sb, b)
+			}
+			if len(sb) < 3 || sb[0] != telnetOptComPort {
+				continue
+			}
+			if sb[1] == comPortSetBaudRate {
+				s.baud = binary.BigEndian.Uint32(sb[2:6])
+			} else {
+				s.settings[sb[1]] = sb[2]
+			}
+			reply := []byte{telnetIAC, telnetSB, telnetOptComPort, sb[1] + comPortServerOffset}
+			reply = append(reply, telnetEscape(sb[2:])...)
+			c.Write(append(reply, telnetIAC, telnetSE))
+		}
+	}
+}
+
+func TestRFC2217Transport(t *testing.T) {
+	s := newFakeSerialServer(t, []string{
+		"#B! 000f0001 i O 00000080 0000ddaa 16",
+		"#B! 000f0002 m I fed40000 000000ff 8",
+		"#B! 000f0004 i O 00000080 0000aadd 16",
+	})
+	defer s.listener.Close()
+
+	cfg := testConfig()
+	cfg.TraceLog.Serial.Type = "rfc2217"
+	cfg.TraceLog.Serial.Port = s.listener.Addr().String()
+	cfg.TraceLog.Serial.BaudRate = 921600
+	cfg.TraceLog.Serial.Parity = "even"
+	cfg.TraceLog.Serial.StopBits = "2"
+
+	tl, err := CreateTraceLog("", 0, "", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 0xff in the config must survive IAC escaping
+	blob := []byte{0xff, 0x00, 0xff, 0xff, 0x12}
+	tles, err := tl.CollectNewTracelog(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-s.dut.done
+
+	if s.baud != 921600 {
+		t.Errorf("Server got baudrate %d", s.baud)
+	}
+	if s.settings[comPortSetDataSize] != 8 || s.settings[comPortSetParity] != 3 ||
+		s.settings[comPortSetStopSize] != 2 {
+		t.Errorf("Server got wrong line settings %v", s.settings)
+	}
+	if !bytes.Equal(s.dut.config, blob) {
+		t.Errorf("DUT received config %x, want %x", s.dut.config, blob)
+	}
+	if len(tles) != 2 || tles[0].Value != 0xff {
+		t.Errorf("Got wrong trace %v", tles)
+	}
+}
+
+func TestTelnetDecode(t *testing.T) {
+	tr := &rfc2217Transport{acks: map[byte][]byte{}}
+	raw := []byte{'a', telnetIAC, telnetIAC, 'b', telnetIAC, 241,
+		telnetIAC, telnetSB, telnetOptComPort, 101, 0, 1, 0xc2, 0, telnetIAC, telnetSE, 'c'}
+	// split input to test the parser state across reads
+	data := append(tr.decode(raw[:5]), tr.decode(raw[5:])...)
+	if !bytes.Equal(data, []byte{'a', 0xff, 'b', 'c'}) {
+		t.Errorf("Decoded %x", data)
+	}
+	if !bytes.Equal(tr.acks[101], []byte{0, 1, 0xc2, 0}) {
+		t.Errorf("Wrong baudrate ack %x", tr.acks[101])
+	}
+}
+
+func TestRFC2217ConcurrentRead(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	tr := &rfc2217Transport{conn: client, acks: map[byte][]byte{}}
+
+	// Every data byte is an escaped IAC, a corrupted parser returns other bytes
+	const count = 200
+	go func() {
+		for i := 0; i < count; i++ {
+			server.Write([]byte{telnetIAC, telnetIAC, telnetIAC, 241})
+		}
+		server.Close()
+	}()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	got := 0
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b := make([]byte, 1)
+			for {
+				n, err := tr.Read(b)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				if n != 1 || b[0] != telnetIAC {
+					t.Errorf("Read %x", b[:n])
+				}
+				got += n
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if got != count {
+		t.Errorf("Read %d of %d bytes", got, count)
+	}
+}
@@ -0,0 +1,57 @@
+package tracelog
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// sessionLogTimeFormat - Format of the host time in front of every line
+const sessionLogTimeFormat = "2006-01-02 15:04:05.000000"
+
+// sessionLog - Raw copy of everything received from the DUT
+// Every line is prefixed with the host time the first char of that line was received.
+type sessionLog struct {
+	mutex     sync.Mutex
+	buf       bytes.Buffer
+	lineStart bool
+}
+
+func (s *sessionLog) reset() {
+	s.mutex.Lock()
+	s.buf.Reset()
+	s.lineStart = true
+	s.mutex.Unlock()
+}
+
+// received - Append a single received char
+func (s *sessionLog) received(b byte, ts time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.lineStart {
+		s.buf.WriteString("[" + ts.Format(sessionLogTimeFormat) + "] ")
+		s.lineStart = false
+	}
+	s.buf.WriteByte(b)
+	if b == '\n' {
+		s.lineStart = true
+	}
+}
+
+// bytes - Returns a copy of the log
+func (s *sessionLog) bytes() []byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]byte{}, s.buf.Bytes()...)
+}
+
+// note - Append a line generated by autorev, e.g. to separate attempts
+func (s *sessionLog) note(text string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.lineStart {
+		s.buf.WriteByte('\n')
+	}
+	s.buf.WriteString("[" + time.Now().Format(sessionLogTimeFormat) + "] " + text + "\n")
+	s.lineStart = true
+}
@@ -0,0 +1,166 @@
+package tracelog
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// DefaultStreamBatchSize - Entries written in a single transaction
+	DefaultStreamBatchSize = 1000
+	// DefaultStreamFlushInterval - Maximum time an entry waits for its batch
+	DefaultStreamFlushInterval = time.Second
+	// streamBuffer - Entries the capture can be ahead of the writer
+	streamBuffer = 4096
+)
+
+// EntryWriter - Persists trace entries of a single test
+type EntryWriter interface {
+	// WriteEntries - Store a batch of entries, e.g. in a single transaction
+	WriteEntries(entries []TraceLogEntry) error
+	// ResetEntries - Remove all entries stored so far, e.g. of a failed attempt
+	ResetEntries() error
+}
+
+// StreamStats - Throughput of a Stream
+type StreamStats struct {
+	// Entries written
+	Entries int
+	// Batches written
+	Batches int
+	// Time spent in WriteEntries
+	Writing time.Duration
+	// Time between NewStream and Close
+	Elapsed time.Duration
+}
+
+// String - Human readable throughput
+func (s StreamStats) String() string {
+	rate := 0.0
+	if s.Elapsed > 0 {
+		rate = float64(s.Entries) / s.Elapsed.Seconds()
+	}
+	return fmt.Sprintf("%d entries in %d batches, %.1f entries/s, %v writing",
+		s.Entries, s.Batches, rate, s.Writing.Round(time.Millisecond))
+}
+
+// streamItem - An entry or, if reset is set, the request to drop all previous entries
+type streamItem struct {
+	entry TraceLogEntry
+	reset bool
+}
+
+// Stream - Passes captured entries over a channel to a writer goroutine, which
+// stores them in batches while the capture is still running. If the capture
+// dies, everything up to the last batch has been written already.
+type Stream struct {
+	items    chan streamItem
+	done     chan struct{}
+	writer   EntryWriter
+	batch    int
+	interval time.Duration
+	started  time.Time
+
+	// owned by the writer goroutine until done is closed
+	stats StreamStats
+	err   error
+}
+
+// NewStream - Start a writer goroutine. A batchSize of 0 and an interval of 0
+// select the defaults.
+func NewStream(w EntryWriter, batchSize int, interval time.Duration) *Stream {
+	if batchSize <= 0 {
+		batchSize = DefaultStreamBatchSize
+	}
+	if interval <= 0 {
+		interval = DefaultStreamFlushInterval
+	}
+	s := &Stream{
+		items:    make(chan streamItem, streamBuffer),
+		done:     make(chan struct{}),
+		writer:   w,
+		batch:    batchSize,
+		interval: interval,
+		started:  time.Now(),
+	}
+	go s.run()
+	return s
+}
+
+// Add - Queue an entry for writing
+func (s *Stream) Add(tle TraceLogEntry) {
+	s.items <- streamItem{entry: tle}
+}
+
+// Reset - Drop all entries written so far, used when a test is retried
+// Write errors and statistics of the entries dropped are cleared.
+func (s *Stream) Reset() {
+	s.items <- streamItem{reset: true}
+}
+
+// Close - Write the remaining entries and stop the writer goroutine
+// Returns the throughput and the first write error.
+func (s *Stream) Close() (StreamStats, error) {
+	close(s.items)
+	<-s.done
+	s.stats.Elapsed = time.Since(s.started)
+	return s.stats, s.err
+}
+
+// flush - Write the pending entries, after an error everything is dropped
+func (s *Stream) flush(pending []TraceLogEntry) {
+	if len(pending) == 0 || s.err != nil {
+		return
+	}
+	n := time.Now()
+	err := s.writer.WriteEntries(pending)
+	s.stats.Writing += time.Since(n)
+	if err != nil {
+		s.err = fmt.Errorf("Failed to write trace entries: %v", err)
+		log.Printf("%v\n", s.err)
+		return
+	}
+	s.stats.Entries += len(pending)
+	s.stats.Batches++
+}
+
+func (s *Stream) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	var pending []TraceLogEntry
+	for {
+		select {
+		case item, ok := <-s.items:
+			if !ok {
+				s.flush(pending)
+				return
+			}
+			if item.reset {
+				// The retry starts over, a write error of the failed
+				// attempt must not drop its entries
+				pending = nil
+				s.err = s.writer.ResetEntries()
+				s.stats = StreamStats{}
+				continue
+			}
+			pending = append(pending, item.entry)
+			if len(pending) >= s.batch {
+				s.flush(pending)
+				pending = nil
+			}
+		case <-ticker.C:
+			s.flush(pending)
+			pending = nil
+		}
+	}
+}
+
+// SetStream - Write entries to s while they are captured, nil to return them
+// from CollectNewTracelog instead
+func (tl *TraceLog) SetStream(s *Stream) {
+	tl.stream = s
+}
@@ -0,0 +1,178 @@
+package tracelog
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memWriter - EntryWriter keeping batches in memory
+type memWriter struct {
+	mutex   sync.Mutex
+	batches [][]TraceLogEntry
+	resets  int
+	fail    bool
+}
+
+func (w *memWriter) WriteEntries(entries []TraceLogEntry) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.fail {
+		return fmt.Errorf("disk full")
+	}
+	w.batches = append(w.batches, append([]TraceLogEntry{}, entries...))
+	return nil
+}
+
+func (w *memWriter) ResetEntries() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.batches = nil
+	w.resets++
+	return nil
+}
+
+func (w *memWriter) entries() []TraceLogEntry {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	var ret []TraceLogEntry
+	for _, b := range w.batches {
+		ret = append(ret, b...)
+	}
+	return ret
+}
+
+func TestStreamBatches(t *testing.T) {
+	w := &memWriter{}
+	s := NewStream(w, 4, time.Hour)
+	for i := 0; i < 10; i++ {
+		s.Add(TraceLogEntry{IP: uint(i)})
+	}
+	stats, err := s.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 10 || stats.Batches != 3 {
+		t.Errorf("Got %s, want 10 entries in 3 batches", stats)
+	}
+	got := w.entries()
+	for i := range got {
+		if got[i].IP != uint(i) {
+			t.Fatalf("Entry %d has IP %d", i, got[i].IP)
+		}
+	}
+	if len(got) != 10 {
+		t.Errorf("Wrote %d entries, want 10", len(got))
+	}
+}
+
+func TestStreamFlushInterval(t *testing.T) {
+	w := &memWriter{}
+	s := NewStream(w, 1000, 10*time.Millisecond)
+	defer s.Close()
+	s.Add(TraceLogEntry{IP: 1})
+
+	// A partial trace must reach storage without waiting for the batch to fill
+	for i := 0; i < 100 && len(w.entries()) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(w.entries()) != 1 {
+		t.Errorf("Entry not flushed")
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	w := &memWriter{fail: true}
+	s := NewStream(w, 2, time.Hour)
+	for i := 0; i < 5; i++ {
+		s.Add(TraceLogEntry{IP: uint(i)})
+	}
+	stats, err := s.Close()
+	if err == nil {
+		t.Errorf("Expected write error")
+	}
+	if stats.Entries != 0 {
+		t.Errorf("Got %d entries written, want 0", stats.Entries)
+	}
+}
+
+// flakyWriter - memWriter failing the first writes
+type flakyWriter struct {
+	memWriter
+	failures int
+}
+
+func (w *flakyWriter) WriteEntries(entries []TraceLogEntry) error {
+	w.mutex.Lock()
+	if w.failures > 0 {
+		w.failures--
+		w.mutex.Unlock()
+		return fmt.Errorf("disk full")
+	}
+	w.mutex.Unlock()
+	return w.memWriter.WriteEntries(entries)
+}
+
+func TestStreamResetClearsError(t *testing.T) {
+	w := &flakyWriter{failures: 1}
+	s := NewStream(w, 2, time.Hour)
+	s.Add(TraceLogEntry{IP: 1})
+	s.Add(TraceLogEntry{IP: 2})
+	s.Add(TraceLogEntry{IP: 3})
+
+	// The retry is written after the failure is gone
+	s.Reset()
+	for i := 0; i < 3; i++ {
+		s.Add(TraceLogEntry{IP: uint(10 + i)})
+	}
+	stats, err := s.Close()
+	if err != nil {
+		t.Fatalf("Error of the failed attempt kept: %v", err)
+	}
+	if stats.Entries != 3 || stats.Batches != 2 {
+		t.Errorf("Got %s, want 3 entries in 2 batches", stats)
+	}
+	if got := w.entries(); len(got) != 3 || got[0].IP != 10 {
+		t.Errorf("Wrong entries %v", got)
+	}
+}
+
+func TestCollectStreamRetry(t *testing.T) {
+	tr := &rebootTransport{traces: [][]string{
+		watchdogTrace[:2],
+		watchdogTrace,
+	}}
+
+	cfg := testConfig()
+	cfg.TraceLog.Watchdog.Inactivity = 1
+	cfg.TraceLog.Watchdog.Retries = 1
+	tl, err := CreateTraceLog("", 0, "", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tl.SetTransport(tr)
+
+	w := &memWriter{}
+	s := NewStream(w, 1, time.Hour)
+	tl.SetStream(s)
+	tles, err := tl.CollectNewTracelog([]byte{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := s.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tles != nil {
+		t.Errorf("Streamed entries were returned too")
+	}
+	// Each attempt starts with a reset, the hung attempt's entry is dropped
+	if w.resets != 2 || len(w.entries()) != 2 || stats.Entries != 2 {
+		t.Errorf("Got %d resets and %d entries (%s), want 2 and 2", w.resets, len(w.entries()), stats)
+	}
+	if a := tl.Attempts(); len(a) != 2 || a[0].Entries != 1 || a[1].Entries != 2 {
+		t.Errorf("Wrong attempts %v", a)
+	}
+}
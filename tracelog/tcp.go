@@ -0,0 +1,96 @@
+package tracelog
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/9elements/autorev/config"
+)
+
+// tcpTransport - A TCP socket, like qemu's "-serial tcp:", ser2net or BMC SOL proxies
+// Connects to Port ("host:port") or, if Listen is set, waits for the DUT to connect.
+type tcpTransport struct {
+	addr     string
+	listen   bool
+	listener net.Listener
+	conn     net.Conn
+}
+
+func init() {
+	RegisterTransport("tcp", newTCPTransport)
+}
+
+func newTCPTransport(cfg config.Config) (Transport, error) {
+	if _, _, err := net.SplitHostPort(cfg.TraceLog.Serial.Port); err != nil {
+		return nil, fmt.Errorf("Serial port must be 'host:port' for type tcp: %v", err)
+	}
+	t := &tcpTransport{
+		addr:   cfg.TraceLog.Serial.Port,
+		listen: cfg.TraceLog.Serial.Listen,
+	}
+	// Listen right away, as the DUT might connect before Open is called
+	if t.listen {
+		var err error
+		t.listener, err = net.Listen("tcp", t.addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Open - Poll until the remote end accepts the connection or connects to us
+func (t *tcpTransport) Open(timeout uint) error {
+	var err error
+	limit := time.Duration(timeout) * time.Second
+
+	if t.listen {
+		return t.accept(limit)
+	}
+
+	n := time.Now()
+	for time.Since(n) < limit {
+		t.conn, err = net.DialTimeout("tcp", t.addr, limit-time.Since(n))
+		if err == nil {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.conn = nil
+	return fmt.Errorf("Timeout connecting to %s: %v", t.addr, err)
+}
+
+// accept - Wait for the DUT to connect to our listening socket
+func (t *tcpTransport) accept(limit time.Duration) error {
+	var err error
+
+	log.Printf("Waiting for connection on %s\n", t.listener.Addr())
+
+	t.listener.(*net.TCPListener).SetDeadline(time.Now().Add(limit))
+	t.conn, err = t.listener.Accept()
+	if err != nil {
+		t.conn = nil
+		return fmt.Errorf("Timeout waiting for connection on %s: %v", t.addr, err)
+	}
+	return nil
+}
+
+func (t *tcpTransport) Read(b []byte) (int, error) {
+	return t.conn.Read(b)
+}
+
+func (t *tcpTransport) Write(b []byte) (int, error) {
+	return t.conn.Write(b)
+}
+
+// Close - Close the connection. The listening socket is kept open for the next run.
+func (t *tcpTransport) Close() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
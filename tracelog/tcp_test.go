@@ -0,0 +1,88 @@
+package tracelog
+
+import (
+	"net"
+	"testing"
+)
+
+func TestTCPTransportConnect(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		c.Write([]byte("#B>"))
+		c.Close()
+	}()
+
+	cfg := testConfig()
+	cfg.TraceLog.Serial.Type = "tcp"
+	cfg.TraceLog.Serial.Port = l.Addr().String()
+	tr, err := NewTransport(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tr.Open(1); err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+
+	b := make([]byte, 3)
+	if n, err := tr.Read(b); err != nil || string(b[:n]) != "#B>" {
+		t.Errorf("Read %q, %v", b[:n], err)
+	}
+}
+
+func TestTCPTransportListen(t *testing.T) {
+	cfg := testConfig()
+	cfg.TraceLog.Serial.Type = "tcp"
+	cfg.TraceLog.Serial.Port = "127.0.0.1:0"
+	cfg.TraceLog.Serial.Listen = true
+	tr, err := NewTransport(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := tr.(*tcpTransport).listener.Addr().String()
+	defer tr.(*tcpTransport).listener.Close()
+
+	// The DUT connects before the host calls Open
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err = tr.Open(1); err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+
+	if _, err = tr.Write([]byte("0: \n")); err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, 4)
+	if n, err := c.Read(b); err != nil || string(b[:n]) != "0: \n" {
+		t.Errorf("Read %q, %v", b[:n], err)
+	}
+}
+
+func TestTCPTransportTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.TraceLog.Serial.Type = "tcp"
+	cfg.TraceLog.Serial.Port = "127.0.0.1:0"
+	cfg.TraceLog.Serial.Listen = true
+	tr, err := NewTransport(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.(*tcpTransport).listener.Close()
+
+	if err = tr.Open(0); err == nil {
+		t.Errorf("Expected timeout without DUT connecting")
+	}
+}
@@ -0,0 +1,95 @@
+package tracelog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PostCodePort - IO port firmware writes POST codes to
+const PostCodePort = 0x80
+
+// TimingSpan - Time spent between two consecutive marks of a trace
+type TimingSpan struct {
+	// Labels of the marks, e.g. "post 9801" or "window fspm"
+	From string
+	To   string
+	// Number of entries between the marks
+	Entries int
+	// Host time between the marks, zero if the entries have no receive time
+	Host time.Duration
+	// DUT time stamp counter ticks between the marks, zero if not sent
+	Ticks uint64
+}
+
+// timingMark - Entry a span starts or ends at
+type timingMark struct {
+	index int
+	label string
+}
+
+// markLabel - Returns the label if the entry is a POST code or starts a new window
+func markLabel(tles []TraceLogEntry, i int, port uint) string {
+	var labels []string
+	if i > 0 && tles[i].Window != tles[i-1].Window {
+		labels = append(labels, "window "+tles[i].Window)
+	}
+	if tles[i].Type == int(IO) && !tles[i].Inout && tles[i].Address == port {
+		labels = append(labels, fmt.Sprintf("post %x", tles[i].Value))
+	}
+	return strings.Join(labels, ", ")
+}
+
+// TimingReport - Splits a trace at POST codes written to port and at capture
+// window changes and returns the time spent in between
+func TimingReport(tles []TraceLogEntry, port uint) []TimingSpan {
+	if len(tles) == 0 {
+		return nil
+	}
+
+	marks := []timingMark{{0, "start"}}
+	if l := markLabel(tles, 0, port); l != "" {
+		marks[0].label = l
+	}
+	for i := 1; i < len(tles); i++ {
+		if l := markLabel(tles, i, port); l != "" {
+			marks = append(marks, timingMark{i, l})
+		}
+	}
+	if marks[len(marks)-1].index != len(tles)-1 {
+		marks = append(marks, timingMark{len(tles) - 1, "end"})
+	}
+
+	var spans []TimingSpan
+	for k := 1; k < len(marks); k++ {
+		from, to := &tles[marks[k-1].index], &tles[marks[k].index]
+		s := TimingSpan{
+			From:    marks[k-1].label,
+			To:      marks[k].label,
+			Entries: marks[k].index - marks[k-1].index,
+		}
+		if !from.Received.IsZero() && !to.Received.IsZero() {
+			s.Host = to.Received.Sub(from.Received)
+		}
+		if from.TSC != 0 && to.TSC >= from.TSC {
+			s.Ticks = to.TSC - from.TSC
+		}
+		spans = append(spans, s)
+	}
+	return spans
+}
+
+// FormatTimingReport - Formats the spans as table, slowest spans are easy to
+// spot in the host time column
+func FormatTimingReport(spans []TimingSpan) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-24s %-24s %8s %14s %16s\n", "from", "to", "entries", "host ms", "tsc ticks")
+	var total time.Duration
+	for _, s := range spans {
+		fmt.Fprintf(&b, "%-24s %-24s %8d %14.3f %16d\n", s.From, s.To, s.Entries,
+			float64(s.Host)/float64(time.Millisecond), s.Ticks)
+		total += s.Host
+	}
+	fmt.Fprintf(&b, "total %.3f ms\n", float64(total)/float64(time.Millisecond))
+	return b.String()
+}
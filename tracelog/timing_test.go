@@ -0,0 +1,65 @@
+package tracelog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimingReport(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return t0.Add(time.Duration(ms) * time.Millisecond) }
+	tles := []TraceLogEntry{
+		{Type: int(IO), Address: PostCodePort, Value: 0x10, AccessSize: 8, Received: at(0), TSC: 1000},
+		{Type: int(IO), Inout: true, Address: 0x508, AccessSize: 32, Received: at(1), TSC: 2000},
+		{Type: int(IO), Inout: true, Address: 0x508, AccessSize: 32, Received: at(40), TSC: 90000},
+		{Type: int(IO), Address: PostCodePort, Value: 0x11, AccessSize: 8, Received: at(41), TSC: 91000},
+		{Type: int(MEM32), Inout: true, Address: 0xfed40000, AccessSize: 32, Received: at(45), TSC: 95000, Window: "fsps"},
+		{Type: int(MEM32), Inout: true, Address: 0xfed40004, AccessSize: 32, Received: at(46), TSC: 96000, Window: "fsps"},
+	}
+	want := []TimingSpan{
+		{From: "post 10", To: "post 11", Entries: 3, Host: 41 * time.Millisecond, Ticks: 90000},
+		{From: "post 11", To: "window fsps", Entries: 1, Host: 4 * time.Millisecond, Ticks: 4000},
+		{From: "window fsps", To: "end", Entries: 1, Host: time.Millisecond, Ticks: 1000},
+	}
+	got := TimingReport(tles, PostCodePort)
+	if len(got) != len(want) {
+		t.Fatalf("Got %d spans, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("Span %d is %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Without timestamps only the entry counts are known
+	for i := range tles {
+		tles[i].Received = time.Time{}
+		tles[i].TSC = 0
+	}
+	got = TimingReport(tles, PostCodePort)
+	if got[0].Host != 0 || got[0].Ticks != 0 || got[0].Entries != 3 {
+		t.Errorf("Wrong span without timestamps %+v", got[0])
+	}
+}
+
+func TestParseLineTSC(t *testing.T) {
+	tle, err := ParseLine("#B! 0000f000 i O 00000080 00000010 8 @00000012a05f2000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tle.TSC != 0x12a05f2000 || tle.Value != 0x10 || tle.AccessSize != 8 {
+		t.Errorf("Wrong entry %s TSC %x", tle.String(), tle.TSC)
+	}
+
+	frame, err := EncodeBinaryEntry(tle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decodeBinaryPayload(frame[3 : len(frame)-2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *tle {
+		t.Errorf("Decoded %s TSC %x, want TSC %x", got.String(), got.TSC, tle.TSC)
+	}
+}
@@ -3,15 +3,10 @@ package tracelog
 import (
 	"fmt"
 	"log"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
-	"go.bug.st/serial.v1"
-
 	"github.com/9elements/autorev/config"
 )
 
@@ -29,6 +24,10 @@ const (
 	CPUID
 	// PCI - PCI register access
 	PCI
+	// MEM64 - Memory access with 64bit address, 8 to 64 bit wide
+	MEM64
+	// STRIO - String IO, rep ins/outs
+	STRIO
 )
 
 // TraceLogEntry - Parse line into Line struct
@@ -45,6 +44,18 @@ type TraceLogEntry struct {
 	Value uint64
 	// 8, 16, 32, 64 bit
 	AccessSize uint
+	// Name of the capture window
+	Window string
+	// CPUID only: ECX input
+	Subleaf uint
+	// CPUID only: EAX, EBX, ECX, EDX output
+	Regs [4]uint32
+	// STRIO only: number of elements transferred, Value is the buffer address
+	Count uint
+	// Time stamp counter of the DUT, 0 if not sent
+	TSC uint64
+	// Host time the entry was received, zero if unknown
+	Received time.Time
 }
 
 // TraceLog - Structure were we hold the general TraceLog Informations
@@ -52,12 +63,38 @@ type TraceLog struct {
 	cfg config.Config
 	// Database connection
 	inputChannel chan string
-	// serial connection
-	serialConn serial.Port
-	// fifo connection
-	fifoFile1, fifoFile2 *os.File
+	// link to the DUT, created from cfg on first use
+	transport Transport
+	// everything received during the last CollectNewTracelog
+	session sessionLog
+	// BL_FAKE rules to upload before start
+	fakeRules []FakeRule
+	// BL_FILTER filters to upload before start
+	filters []Filter
 	// verbosity
 	verbose bool
+	// attempts made by the last CollectNewTracelog
+	attempts []Attempt
+	// true while reading the trace, selects the inactivity watchdog
+	tracing bool
+	// wall-clock watchdog of the current attempt, zero if disabled
+	deadline time.Time
+	// test passed to DUT control commands
+	testID int
+	// config blob of the current test, passed to DUT control commands
+	configPath string
+	// DUT control commands run by the last CollectNewTracelog
+	commands []CommandResult
+	// CollectNewTracelog has run, the init command is only stored with the first test
+	collected bool
+	// powers the DUT on and off
+	dut DutController
+	// take serial type and port from the DUT controller
+	discoverSerial bool
+	// receives the captured entries while tracing, if set
+	stream *Stream
+	// number of entries captured by the last attempt
+	captured int
 }
 
 // ConvertToType - Convert a string into a Type
@@ -73,10 +110,35 @@ func ConvertToType(inputType string) int {
 		return int(CPUID)
 	case "p":
 		return int(PCI)
+	case "M":
+		return int(MEM64)
+	case "r":
+		return int(STRIO)
 	}
 	return -1
 }
 
+// ConvertFromType - Convert a Type into a string, see ConvertToType
+func ConvertFromType(t int) string {
+	switch t {
+	case int(MEM32):
+		return "m"
+	case int(IO):
+		return "i"
+	case int(MSR):
+		return "s"
+	case int(CPUID):
+		return "c"
+	case int(PCI):
+		return "p"
+	case int(MEM64):
+		return "M"
+	case int(STRIO):
+		return "r"
+	}
+	return ""
+}
+
 // ConvertToDir - Convert InputDirection into Bool
 func ConvertToDir(inputDirection string) bool {
 	switch inputDirection {
@@ -109,6 +171,18 @@ func (tle *TraceLogEntry) String() string {
 		tracelogtype = "c"
 	case int(PCI):
 		tracelogtype = "p"
+	case int(MEM64):
+		tracelogtype = "M"
+	case int(STRIO):
+		tracelogtype = "r"
+	}
+	if tle.Type == int(CPUID) {
+		return fmt.Sprintf("IP: %08x, Type: %s, Dir: %s, Leaf: %08x, Subleaf: %08x, Regs: %08x %08x %08x %08x",
+			tle.IP, tracelogtype, dir, tle.Address, tle.Subleaf, tle.Regs[0], tle.Regs[1], tle.Regs[2], tle.Regs[3])
+	}
+	if tle.Type == int(STRIO) {
+		return fmt.Sprintf("IP: %08x, Type: %s, Dir: %s, Port: %04x, Buffer: %08x, Count: %d, Access: %d",
+			tle.IP, tracelogtype, dir, tle.Address, tle.Value, tle.Count, tle.AccessSize)
 	}
 	return fmt.Sprintf("IP: %08x, Type: %s, Dir: %s, Addr: %08x, Value: %016x, Access: %d",
 		tle.IP, tracelogtype, dir, tle.Address, tle.Value, tle.AccessSize)
@@ -135,6 +209,18 @@ func (tle *TraceLogEntry) SmallString() string {
 		tracelogtype = "c"
 	case int(PCI):
 		tracelogtype = "p"
+	case int(MEM64):
+		tracelogtype = "M"
+	case int(STRIO):
+		tracelogtype = "r"
+	}
+	if tle.Type == int(CPUID) {
+		return fmt.Sprintf("%s%s %08x.%x %08x %08x %08x %08x",
+			tracelogtype, dir, tle.Address, tle.Subleaf, tle.Regs[0], tle.Regs[1], tle.Regs[2], tle.Regs[3])
+	}
+	if tle.Type == int(STRIO) {
+		return fmt.Sprintf("%s%s %04x %08x %dx%d",
+			tracelogtype, dir, tle.Address, tle.Value, tle.Count, tle.AccessSize)
 	}
 	return fmt.Sprintf("%s%s %08x %016x %d",
 		tracelogtype, dir, tle.Address, tle.Value, tle.AccessSize)
@@ -151,10 +237,19 @@ func ParseLine(line string) (*TraceLogEntry, error) {
 	if parts[0] != "#B!" {
 		return nil, fmt.Errorf("Line doesn't start with trace prefix")
 	}
+	// Optional time stamp counter at the end
+	if last := parts[len(parts)-1]; len(parts) > 1 && strings.HasPrefix(last, "@") {
+		tsc, err := strconv.ParseUint(last[1:], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		new.TSC = tsc
+		parts = parts[:len(parts)-1]
+	}
 	if len(parts) == 1 {
 		return nil, fmt.Errorf("Line doesn't have IP value")
 	}
-	i, err := strconv.ParseInt(parts[1], 16, 64)
+	i, err := strconv.ParseUint(parts[1], 16, 64)
 	if err != nil {
 		return nil, err
 	}
@@ -180,7 +275,7 @@ func ParseLine(line string) (*TraceLogEntry, error) {
 	if len(parts) == 4 {
 		return nil, fmt.Errorf("Line doesn't have Address value")
 	}
-	i, err = strconv.ParseInt(parts[4], 16, 64)
+	i, err = strconv.ParseUint(parts[4], 16, 64)
 	if err != nil {
 		return nil, err
 	}
@@ -189,34 +284,72 @@ func ParseLine(line string) (*TraceLogEntry, error) {
 	if len(parts) == 5 {
 		return nil, fmt.Errorf("Line doesn't have Value value")
 	}
-	i, err = strconv.ParseInt(parts[5], 16, 64)
+	i, err = strconv.ParseUint(parts[5], 16, 64)
 	if err != nil {
 		return nil, err
 	}
-	new.Value = uint64(i)
+	new.Value = i
 
 	if t == int(MSR) {
 		if len(parts) == 6 {
 			return nil, fmt.Errorf("Line doesn't have second Value value")
 		}
-		i, err = strconv.ParseInt(parts[6], 16, 64)
+		i, err = strconv.ParseUint(parts[6], 16, 32)
 		if err != nil {
 			return nil, err
 		}
 
-		new.Value |= uint64(i) << 32
+		new.Value |= i << 32
 	}
 
-	if t == int(IO) || t == int(MEM32) || t == int(PCI) {
+	if t == int(CPUID) {
+		// Old format: only the EAX output as VALUE
 		if len(parts) == 6 {
+			new.Regs[0] = uint32(new.Value)
+			return &new, nil
+		}
+		// New format: subleaf followed by EAX, EBX, ECX, EDX output
+		if len(parts) < 10 {
+			return nil, fmt.Errorf("Line doesn't have all CPUID registers")
+		}
+		new.Subleaf = uint(new.Value)
+		for j := range new.Regs {
+			i, err = strconv.ParseUint(parts[6+j], 16, 32)
+			if err != nil {
+				return nil, err
+			}
+			new.Regs[j] = uint32(i)
+		}
+		new.Value = uint64(new.Regs[0])
+	}
+
+	// The count of string IO comes before the access size
+	sizeIndex := 6
+	if t == int(STRIO) {
+		if len(parts) == 6 {
+			return nil, fmt.Errorf("Line doesn't have Count value")
+		}
+		i, err = strconv.ParseUint(parts[6], 16, 32)
+		if err != nil {
+			return nil, err
+		}
+		new.Count = uint(i)
+		sizeIndex = 7
+	}
+
+	if t == int(IO) || t == int(MEM32) || t == int(PCI) || t == int(MEM64) || t == int(STRIO) {
+		if len(parts) == sizeIndex {
 			return nil, fmt.Errorf("Line doesn't have AccessSize value")
 		}
-		i, err = strconv.ParseInt(parts[6], 10, 32)
+		i, err = strconv.ParseUint(parts[sizeIndex], 10, 32)
 		if err != nil {
 			return nil, err
 		}
 		new.AccessSize = uint(i)
 	}
+	if t == int(MEM64) && new.AccessSize != 8 && new.AccessSize != 16 && new.AccessSize != 32 && new.AccessSize != 64 {
+		return nil, fmt.Errorf("Line has invalid AccessSize value")
+	}
 
 	return &new, nil
 }
@@ -229,20 +362,9 @@ func CreateTraceLog(devTTYDevicePath string, baudTTYDevice int, fifoDevicePath s
 	if len(devTTYDevicePath) > 0 && len(fifoDevicePath) > 0 {
 		return nil, fmt.Errorf("Cannot specify both: -fifo and -dev")
 	}
-	if len(cfg.TraceLog.Serial.Port) == 0 {
-		return nil, fmt.Errorf("Collecting a new trace, but serial port not specified")
-	}
-	if cfg.TraceLog.Serial.Type != "fifo" && cfg.TraceLog.Serial.Type != "tty" {
-		return nil, fmt.Errorf("Collecting a new trace, but serial type is unknown (must be 'fifo' or 'tty')")
-	}
 
 	t.cfg = cfg
 
-	// Execute shell command to get serial and DUT control
-	if len(t.cfg.TraceLog.DutControl.InitCmd) > 0 {
-		t.shellcmd(t.cfg.TraceLog.DutControl.InitCmd)
-	}
-
 	// overwrite config
 	if len(devTTYDevicePath) > 0 {
 		t.cfg.TraceLog.Serial.Type = "tty"
@@ -256,125 +378,98 @@ func CreateTraceLog(devTTYDevicePath string, baudTTYDevice int, fifoDevicePath s
 		t.cfg.TraceLog.Serial.BaudRate = baudTTYDevice
 	}
 
-	return &t, nil
-}
+	// QEMU tells where its serial is
+	t.discoverSerial = len(t.cfg.TraceLog.Serial.Port) == 0 && t.cfg.TraceLog.DutControl.Type == "qmp"
 
-// SetVerbose - Set verbosity of tracing
-func (tl *TraceLog) SetVerbose(v bool) {
-	tl.verbose = v
-}
+	if len(t.cfg.TraceLog.Serial.Port) == 0 && !t.discoverSerial {
+		return nil, fmt.Errorf("Collecting a new trace, but serial port not specified")
+	}
+	if _, ok := transports[t.cfg.TraceLog.Serial.Type]; !ok && !t.discoverSerial {
+		return nil, fmt.Errorf("Collecting a new trace, but serial type is unknown (must be one of '%s')",
+			strings.Join(TransportNames(), "', '"))
+	}
 
-// shellcmd - execute a shell cmd. Allows to run custom scripts that power the DUT
-func (tl *TraceLog) shellcmd(s string) {
-	cmd := strings.Split(s, " ")[0]
-	args := strings.Split(s, " ")[1:]
-	abs, err := filepath.Abs(cmd)
+	var err error
+	t.dut, err = NewDutController(&t)
 	if err != nil {
-		log.Fatal(err)
-	} else {
-		log.Printf("Running shell cmd '%s'\n", abs)
+		return nil, err
+	}
 
-		execcmd := exec.Command(abs, args...)
-		execcmd.Dir = filepath.Dir(abs)
+	// Execute shell command to get serial and DUT control
+	err = t.dut.Init()
+	if err != nil {
+		return nil, err
+	}
 
-		err := execcmd.Run()
+	if !t.discoverSerial {
+		t.transport, err = NewTransport(t.cfg)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 	}
+
+	return &t, nil
+}
+
+// SetTransport - Use the given transport instead of the one selected by config
+func (tl *TraceLog) SetTransport(t Transport) {
+	tl.transport = t
+}
+
+// SetFakeRules - Set the BL_FAKE rules uploaded on the next CollectNewTracelog
+func (tl *TraceLog) SetFakeRules(rules []FakeRule) {
+	tl.fakeRules = rules
+}
+
+// SetFilters - Set the BL_FILTER filters uploaded on the next CollectNewTracelog
+func (tl *TraceLog) SetFilters(filters []Filter) {
+	tl.filters = filters
+}
+
+// CompleteLog - Returns everything received from the DUT during the last
+// CollectNewTracelog, including lines that aren't trace entries
+func (tl *TraceLog) CompleteLog() []byte {
+	return tl.session.bytes()
+}
+
+// SetVerbose - Set verbosity of tracing
+func (tl *TraceLog) SetVerbose(v bool) {
+	tl.verbose = v
 }
 
 // openWaitForSerial - Wait for the serial device to appear
 func (tl *TraceLog) openWaitForSerial(timeout uint) error {
 	var err error
-	n := time.Now()
 	if timeout == 0 {
 		timeout = 5
 	}
-	limit := time.Duration(timeout) * time.Second
 
 	if tl.cfg.TraceLog.Serial.ReadWriteTimeout == 0 {
 		tl.cfg.TraceLog.Serial.ReadWriteTimeout = 5
 	}
 
-	if tl.cfg.TraceLog.Serial.Type == "tty" {
-		if tl.cfg.TraceLog.Serial.BaudRate == 0 {
-			tl.cfg.TraceLog.Serial.BaudRate = 115200
-		}
-		var mode = serial.Mode{
-			tl.cfg.TraceLog.Serial.BaudRate, 8, serial.NoParity, serial.OneStopBit,
-		}
-		for time.Since(n) < limit {
-			// Poll on Serial to open (Testing)
-			tl.serialConn, err = serial.Open(tl.cfg.TraceLog.Serial.Port, &mode)
-			if err == nil {
-				break
-			}
-			time.Sleep(time.Millisecond)
-		}
-		if time.Since(n) >= limit {
-			tl.serialConn = nil
-			return fmt.Errorf("Timeout waiting for serial device")
-		}
-	} else if tl.cfg.TraceLog.Serial.Type == "fifo" {
-		for time.Since(n) < limit {
-			// Poll on FIFO to open (Testing)
-			var res error
-			c1 := make(chan error, 1)
-			go func() {
-				tl.fifoFile1, err = os.OpenFile(tl.cfg.TraceLog.Serial.Port+".in", os.O_WRONLY, 0)
-				if err == nil {
-					tl.fifoFile2, err = os.OpenFile(tl.cfg.TraceLog.Serial.Port+".out", os.O_RDONLY, 0)
-					if err != nil {
-						tl.fifoFile1.Close()
-					}
-				}
-				c1 <- err
-			}()
-			log.Printf("Waiting for open file\n")
-
-			select {
-			case res = <-c1:
-				{
-					if res != nil {
-						return res
-					}
-					if tl.fifoFile2 == nil || tl.fifoFile1 == nil {
-						return fmt.Errorf("Failed to open FIFO")
-					}
-					return nil
-				}
-			case <-time.After(time.Second):
-				{
-				}
-			}
-		}
-		if time.Since(n) >= limit {
-			return fmt.Errorf("Timeout waiting for fifo device")
+	if tl.transport == nil {
+		tl.transport, err = NewTransport(tl.cfg)
+		if err != nil {
+			return err
 		}
 	}
 
-	return nil
+	return tl.transport.Open(timeout)
 }
 
-// read - Reads single char from serial or FIFO with timeout
+// read - Reads single char from the transport with timeout
 func (tl *TraceLog) read() (byte, error) {
 	var res byte
-	limit := time.Duration(tl.cfg.TraceLog.Serial.ReadWriteTimeout) * time.Second
+	limit := tl.readTimeout()
 
 	c1 := make(chan byte, 1)
 	go func() {
 		b := make([]byte, 1)
-		if tl.cfg.TraceLog.Serial.Type == "tty" {
-			n, err := tl.serialConn.Read(b)
-			if err == nil && n == 1 {
-				c1 <- b[0]
-			}
-		} else {
-			n, err := tl.fifoFile2.Read(b)
-			if err == nil && n > 0 {
-				c1 <- b[0]
-			}
+		n, err := tl.transport.Read(b)
+		if err == nil && n == 1 {
+			tl.session.received(b[0], time.Now())
+			c1 <- b[0]
 		}
 	}()
 
@@ -391,11 +486,11 @@ func (tl *TraceLog) read() (byte, error) {
 	return 0, fmt.Errorf("Timeout waiting for serial char")
 }
 
-// readLine - Reads line from serial or FIFO with timeout
+// readLine - Reads line from the transport with timeout
 // Cuts of newline "\n"
 func (tl *TraceLog) readLine() (string, error) {
 	var res string
-	limit := time.Duration(tl.cfg.TraceLog.Serial.ReadWriteTimeout) * time.Second
+	limit := tl.readTimeout()
 
 	n := time.Now()
 
@@ -440,7 +535,7 @@ func (tl *TraceLog) readString(search string) (string, error) {
 	return "", fmt.Errorf("Timeout waiting for serial device")
 }
 
-// Write to Serial or FIFO with timeout
+// Write to the transport with timeout
 func (tl *TraceLog) write(buf []byte) (int, error) {
 	limit := time.Duration(tl.cfg.TraceLog.Serial.ReadWriteTimeout) * time.Second
 
@@ -452,16 +547,9 @@ func (tl *TraceLog) write(buf []byte) (int, error) {
 
 		c1 := make(chan bool, 1)
 		go func(b byte) {
-			if tl.cfg.TraceLog.Serial.Type == "tty" {
-				n, err := tl.serialConn.Write([]byte{b})
-				if err == nil && n == 1 {
-					c1 <- true
-				}
-			} else {
-				n, err := tl.fifoFile1.Write([]byte{b})
-				if err == nil && n == 1 {
-					c1 <- true
-				}
+			n, err := tl.transport.Write([]byte{b})
+			if err == nil && n == 1 {
+				c1 <- true
 			}
 		}(buf[0])
 
@@ -492,39 +580,62 @@ func (tl *TraceLog) writeString(s string) (int, error) {
 	return tl.write([]byte(s))
 }
 
-// Write to Serial or FIFO with timeout
+// close - Close the transport
 func (tl *TraceLog) close() {
-	if tl.cfg.TraceLog.Serial.Type == "tty" {
-		tl.serialConn.Close()
-		tl.serialConn = nil
-	} else {
-		if tl.fifoFile1 != nil {
-			tl.fifoFile1.Close()
-			tl.fifoFile1 = nil
+	tl.transport.Close()
+}
 
-		}
-		if tl.fifoFile2 != nil {
-			tl.fifoFile2.Close()
-			tl.fifoFile2 = nil
+// shellCommand - Send a command to the shell and wait for the next prompt
+// Returns everything received before the prompt
+func (tl *TraceLog) shellCommand(cmd string) (string, error) {
+	_, err := tl.writeString(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	reply, err := tl.readString(">")
+	if err != nil {
+		return "", err
+	}
+	if tl.verbose {
+		s := strings.Split(reply, "\n")
+		for i := range s {
+			log.Printf(">%s<\n", s[i])
 		}
 	}
+	return reply, nil
 }
 
-// CollectNewTracelog - Collects a new Trace Log
-func (tl *TraceLog) CollectNewTracelog(config []byte) ([]TraceLogEntry, error) {
-	var ret []TraceLogEntry
+// collectAttempt - Start or restart the DUT and collect a single Trace Log
+func (tl *TraceLog) collectAttempt(config []byte, restart bool) ([]TraceLogEntry, error) {
 	var err error
 
-	// Execute shell command to get DUT in the running state
-	if len(tl.cfg.TraceLog.DutControl.StartCmd) > 0 {
-		tl.shellcmd(tl.cfg.TraceLog.DutControl.StartCmd)
+	tl.tracing = false
+	tl.deadline = time.Time{}
+	if tl.cfg.TraceLog.Watchdog.Timeout > 0 {
+		tl.deadline = time.Now().Add(time.Duration(tl.cfg.TraceLog.Watchdog.Timeout) * time.Second)
 	}
-	defer func() {
-		// Execute shell command to get DUT in the off state
-		if len(tl.cfg.TraceLog.DutControl.StopCmd) > 0 {
-			tl.shellcmd(tl.cfg.TraceLog.DutControl.StopCmd)
+
+	// Get DUT in the running state
+	if restart {
+		err = tl.dut.Restart()
+	} else {
+		err = tl.dut.Start()
+	}
+	if err != nil {
+		return nil, &TraceError{Reason: FailureDutControl, Err: err}
+	}
+
+	if tl.discoverSerial {
+		serialType, port, err := tl.dut.Serial()
+		if err != nil {
+			return nil, &TraceError{Reason: FailureNoConnection, Err: err}
 		}
-	}()
+		tl.cfg.TraceLog.Serial.Type = serialType
+		tl.cfg.TraceLog.Serial.Port = port
+		tl.cfg.TraceLog.Serial.Listen = false
+		tl.transport = nil
+	}
 
 	// Need to open serial and FIFO here as
 	// 1) xhci debug serial appears only when the DUT enabled the debug port
@@ -532,17 +643,26 @@ func (tl *TraceLog) CollectNewTracelog(config []byte) ([]TraceLogEntry, error) {
 
 	err = tl.openWaitForSerial(tl.cfg.TraceLog.Serial.DeviceHotplugTimeout)
 	if err != nil {
-		return nil, err
+		return nil, &TraceError{Reason: FailureNoConnection, Err: err}
 	}
 	defer tl.close()
 
+	// The DUT might wait for the serial to be opened
+	err = tl.dut.Resume()
+	if err != nil {
+		return nil, &TraceError{Reason: FailureDutControl, Err: err}
+	}
+
 	// Wait for shell to connect
 	for {
+		if err := tl.checkWatchdog(nil); err != nil {
+			return nil, err
+		}
 		tl.write([]byte("\n"))
 		// read shell prefix
 		l, err := tl.readString(">")
 		if err != nil {
-			return ret, fmt.Errorf("Failed to parse shell prefix")
+			return nil, &TraceError{Reason: FailureNoShell, Err: fmt.Errorf("Failed to parse shell prefix")}
 		}
 		if tl.verbose {
 			s := strings.Split(l, "\n")
@@ -556,98 +676,132 @@ func (tl *TraceLog) CollectNewTracelog(config []byte) ([]TraceLogEntry, error) {
 	}
 	log.Printf("Found shell...\n")
 	// Set config
-	offset := 0
-	remain := len(config)
-	for offset < 4096 && offset < remain { // hardcoded in libx86emu
-		tl.writeString("3: ")
-		if remain > 512 {
-			tl.writeString(fmt.Sprintf("%08x", 512))
-			tl.writeString(fmt.Sprintf("%08x", offset))
-			for i := 0; i < 512; i++ {
-				tl.writeString(fmt.Sprintf("%02x", config[i+offset]))
-			}
-
-			offset += 512
-			remain -= 512
+	err = tl.uploadConfig(config)
+	if err != nil {
+		return nil, &TraceError{Reason: FailureConfigUpload, Err: err}
+	}
 
-		} else {
-			tl.writeString(fmt.Sprintf("%08x", remain))
-			tl.writeString(fmt.Sprintf("%08x", offset))
-			for i := 0; i < remain; i++ {
-				tl.writeString(fmt.Sprintf("%02x", config[i+offset]))
+	// Set fake values
+	for i := range tl.fakeRules {
+		log.Printf("Faking %s\n", tl.fakeRules[i].String())
+		payload, err := tl.fakeRules[i].payload()
+		if err != nil {
+			return nil, &TraceError{Reason: FailureShellCommand, Err: err}
+		}
+		reply, err := tl.shellCommand("1: " + payload + "\n")
+		if err != nil {
+			return nil, &TraceError{Reason: FailureShellCommand, Err: err}
+		}
+		if !strings.Contains(reply, "#B+ "+payload) {
+			return nil, &TraceError{
+				Reason: FailureShellCommand,
+				Err:    fmt.Errorf("Shell didn't acknowledge fake rule %s", tl.fakeRules[i].String()),
 			}
-
-			offset += remain
-			remain = 0
 		}
-		tl.writeString("\n")
+	}
 
-		discard, err := tl.readString(">")
+	// Set filters
+	for i := range tl.filters {
+		log.Printf("Filtering %s\n", tl.filters[i].String())
+		payload, err := tl.filters[i].payload()
 		if err != nil {
-			return nil, err
+			return nil, &TraceError{Reason: FailureShellCommand, Err: err}
 		}
-		if tl.verbose {
-			s := strings.Split(discard, "\n")
-			for i := range s {
-				log.Printf(">%s<\n", s[i])
+		reply, err := tl.shellCommand("2: " + payload + "\n")
+		if err != nil {
+			return nil, &TraceError{Reason: FailureShellCommand, Err: err}
+		}
+		if !strings.Contains(reply, "#B+ "+payload) {
+			return nil, &TraceError{
+				Reason: FailureShellCommand,
+				Err:    fmt.Errorf("Shell didn't acknowledge filter %s", tl.filters[i].String()),
 			}
 		}
 	}
 
+	// Negotiate binary trace format. Old shells don't acknowledge it.
+	binaryTrace := false
+	if tl.cfg.TraceLog.BinaryTrace {
+		reply, err := tl.shellCommand("4: 01\n")
+		if err != nil {
+			return nil, &TraceError{Reason: FailureShellCommand, Err: err}
+		}
+		binaryTrace = strings.Contains(reply, "#B+ 01")
+		if !binaryTrace {
+			log.Printf("Shell doesn't support binary trace, using ASCII\n")
+		}
+	}
+
 	// Write start signal
 	tl.writeString("0: \n")
 
 	log.Println("Config written.")
 
-	checkCaptureState := len(tl.cfg.TraceLog.StartSignal.Type) > 0 && len(tl.cfg.TraceLog.StopSignal.Type) > 0
-	capturing := !checkCaptureState
+	capture := NewCapture(tl.cfg)
+	capture.SetVerbose(tl.verbose)
+	capture.SetStream(tl.stream)
+	defer func() {
+		tl.captured = capture.Count()
+	}()
+
+	tl.tracing = true
+	defer func() {
+		tl.tracing = false
+	}()
+
+	if binaryTrace {
+		return tl.collectBinary(capture)
+	}
 
-	var running = true
-	for running {
+	for {
+		if err := tl.checkWatchdog(capture); err != nil {
+			return capture.Entries(), err
+		}
 		buffer, err := tl.readLine()
 		if err != nil {
-			return ret, fmt.Errorf("Failed to read from DUT connection: %s", err.Error())
+			return capture.Entries(), readError(capture, err)
+		}
+
+		capture.SetReceived(time.Now())
+		if capture.AddLine(buffer) {
+			break
 		}
+	}
+	return capture.Entries(), nil
+}
+
+// collectBinary - Read binary trace frames, and text lines in between, until the stop signal
+func (tl *TraceLog) collectBinary(capture *Capture) ([]TraceLogEntry, error) {
+	var decoder binaryDecoder
 
-		inputLog, err := ParseLine(buffer)
+	for {
+		if err := tl.checkWatchdog(capture); err != nil {
+			return capture.Entries(), err
+		}
+		b, err := tl.read()
 		if err != nil {
-			if tl.verbose {
-				log.Printf(">%s<\n", buffer)
+			return capture.Entries(), readError(capture, err)
+		}
+
+		done := false
+		for _, item := range decoder.feed(b) {
+			if item.err != nil {
+				log.Printf("Error ! %v\n", item.err)
+				continue
 			}
-			if capturing {
-				log.Printf("Error ! %v\n", err)
-				log.Printf("Line was >>%s<<\n", buffer)
+			capture.SetReceived(time.Now())
+			if item.tle != nil && capture.AddEntry(item.tle) {
+				done = true
+				break
 			}
-		} else {
-			if checkCaptureState {
-				if capturing {
-					ret = append(ret, *inputLog)
-					log.Printf("%v\n", inputLog)
-					if inputLog.Inout == ConvertToDir(tl.cfg.TraceLog.StopSignal.Direction) &&
-						inputLog.AccessSize == tl.cfg.TraceLog.StopSignal.DataWidth &&
-						inputLog.Value == tl.cfg.TraceLog.StopSignal.Value &&
-						inputLog.Type == ConvertToType(tl.cfg.TraceLog.StopSignal.Type) &&
-						inputLog.Address == tl.cfg.TraceLog.StopSignal.Offset {
-						capturing = false
-						running = false
-						break
-					}
-				}
-				if !capturing {
-					if inputLog.Inout == ConvertToDir(tl.cfg.TraceLog.StartSignal.Direction) &&
-						inputLog.AccessSize == tl.cfg.TraceLog.StartSignal.DataWidth &&
-						inputLog.Value == tl.cfg.TraceLog.StartSignal.Value &&
-						inputLog.Type == ConvertToType(tl.cfg.TraceLog.StartSignal.Type) &&
-						inputLog.Address == tl.cfg.TraceLog.StartSignal.Offset {
-						capturing = true
-						log.Printf("%v\n", inputLog)
-					}
-				}
-			} else {
-				ret = append(ret, *inputLog)
-				log.Printf("%v\n", inputLog)
+			if item.line != nil && capture.AddLine(*item.line) {
+				done = true
+				break
 			}
 		}
+		if done {
+			break
+		}
 	}
-	return ret, nil
+	return capture.Entries(), nil
 }
@@ -0,0 +1,362 @@
+package tracelog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/9elements/autorev/config"
+)
+
+// memTransport - In-memory transport connected to a fakeDUT
+type memTransport struct {
+	r *io.PipeReader
+	w *io.PipeWriter
+}
+
+func (m *memTransport) Open(timeout uint) error     { return nil }
+func (m *memTransport) Read(b []byte) (int, error)  { return m.r.Read(b) }
+func (m *memTransport) Write(b []byte) (int, error) { return m.w.Write(b) }
+func (m *memTransport) Close() error {
+	m.r.Close()
+	m.w.Close()
+	return nil
+}
+
+// fakeDUT - Emulates the autorev shell and prints trace after BL_START
+type fakeDUT struct {
+	trace  []string
+	config []byte
+	fakes  []string
+	done   chan bool
+	// don't acknowledge config chunks
+	legacy bool
+	// number of config chunks to acknowledge with a wrong checksum
+	corrupt int
+	// number of config chunks after the first one without acknowledge
+	dropAcks int
+	// supports binary trace format
+	binary bool
+}
+
+// newFakeDUT - Returns the transport the host side has to use
+func newFakeDUT(trace []string) (*fakeDUT, *memTransport) {
+	hostR, dutW := io.Pipe()
+	dutR, hostW := io.Pipe()
+
+	return startFakeDUT(trace, dutR, dutW), &memTransport{r: hostR, w: hostW}
+}
+
+// startFakeDUT - Run the fake DUT on an arbitrary stream
+func startFakeDUT(trace []string, r io.Reader, w io.WriteCloser) *fakeDUT {
+	d := &fakeDUT{trace: trace, done: make(chan bool)}
+
+	go d.run(bufio.NewReader(r), w)
+
+	return d
+}
+
+func (d *fakeDUT) run(r *bufio.Reader, w io.WriteCloser) {
+	defer close(d.done)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "3: "):
+			// length, offset, data
+			length, _ := strconv.ParseUint(line[3:11], 16, 32)
+			offset, _ := strconv.ParseUint(line[11:19], 16, 32)
+			data, _ := hex.DecodeString(line[19:])
+			if d.legacy && offset >= legacyConfigLimit {
+				break
+			}
+			for uint64(len(d.config)) < offset+length {
+				d.config = append(d.config, 0)
+			}
+			copy(d.config[offset:], data)
+			crc := crc32.ChecksumIEEE(data)
+			if d.corrupt > 0 {
+				d.corrupt--
+				crc++
+			}
+			if offset > 0 && d.dropAcks > 0 {
+				d.dropAcks--
+				break
+			}
+			if !d.legacy {
+				fmt.Fprintf(w, "#B= %08x %08x %08x", offset, len(data), crc)
+			}
+		case strings.HasPrefix(line, "1: "):
+			d.fakes = append(d.fakes, line[3:])
+			io.WriteString(w, "#B+ "+line[3:])
+		case strings.HasPrefix(line, "2: "):
+			io.WriteString(w, "#B+ "+line[3:])
+		case strings.HasPrefix(line, "4: "):
+			if d.binary {
+				io.WriteString(w, "#B+ "+line[3:])
+			}
+		case strings.HasPrefix(line, "0: "):
+			for _, l := range d.trace {
+				if tle, err := ParseLine(l); err == nil && d.binary {
+					frame, _ := EncodeBinaryEntry(tle)
+					w.Write(frame)
+					continue
+				}
+				io.WriteString(w, l+"\r\n")
+			}
+			return
+		}
+		io.WriteString(w, "\r\n#B>")
+	}
+}
+
+func testConfig() config.Config {
+	var cfg config.Config
+	cfg.TraceLog.Serial.Type = "fifo"
+	cfg.TraceLog.Serial.Port = "/nonexistent"
+	cfg.TraceLog.Serial.ReadWriteTimeout = 1
+	cfg.TraceLog.StartSignal.Type = "i"
+	cfg.TraceLog.StartSignal.Offset = 0x80
+	cfg.TraceLog.StartSignal.Value = 0xddaa
+	cfg.TraceLog.StartSignal.Direction = "O"
+	cfg.TraceLog.StartSignal.DataWidth = 16
+	cfg.TraceLog.StopSignal.Type = "i"
+	cfg.TraceLog.StopSignal.Offset = 0x80
+	cfg.TraceLog.StopSignal.Value = 0xaadd
+	cfg.TraceLog.StopSignal.Direction = "O"
+	cfg.TraceLog.StopSignal.DataWidth = 16
+	return cfg
+}
+
+func TestCollectNewTracelog(t *testing.T) {
+	d, tr := newFakeDUT([]string{
+		"#B! 000f0000 i I 00000064 00000001 8",
+		"#B! 000f0001 i O 00000080 0000ddaa 16",
+		"garbage",
+		"#B! 000f0002 p O 00000048 00000030 8",
+		"#B! 000f0003 s I 0000001b 00000000 fee00900",
+		"#B! 000f0004 i O 00000080 0000aadd 16",
+		"#B! 000f0005 i O 00000080 00000000 8",
+	})
+
+	tl, err := CreateTraceLog("", 0, "", testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tl.SetTransport(tr)
+
+	tl.SetFilters([]Filter{{Address: 0x3f8, Type: int(IO)}})
+	tl.SetFakeRules([]FakeRule{{IP: 0xf1234, Address: 0x64, Value: 0x80, Inout: true, Type: int(IO)}})
+
+	blob := []byte{1, 2, 3, 4, 5}
+	tles, err := tl.CollectNewTracelog(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-d.done
+
+	if string(d.config) != string(blob) {
+		t.Errorf("DUT received config %x, want %x", d.config, blob)
+	}
+	if len(d.fakes) != 1 || d.fakes[0] != "000f12340000006400000000000000800101" {
+		t.Errorf("DUT received fakes %v", d.fakes)
+	}
+	if len(tles) != 3 {
+		t.Fatalf("Got %d entries, want 3", len(tles))
+	}
+	if tles[0].Type != int(PCI) || tles[0].Address != 0x48 || tles[0].Value != 0x30 {
+		t.Errorf("Wrong first entry %s", tles[0].String())
+	}
+	if tles[1].Type != int(MSR) || tles[1].Value != 0xfee0090000000000 {
+		t.Errorf("Wrong MSR entry %s", tles[1].String())
+	}
+	if tles[2].Value != 0xaadd {
+		t.Errorf("Stop signal not part of trace %s", tles[2].String())
+	}
+	if tles[0].Received.IsZero() || tles[2].Received.Before(tles[0].Received) {
+		t.Errorf("Wrong receive times %v %v", tles[0].Received, tles[2].Received)
+	}
+	if !strings.Contains(string(tl.CompleteLog()), "] garbage\r\n") {
+		t.Errorf("Complete log misses unparsable line:\n%s", tl.CompleteLog())
+	}
+}
+
+func TestCreateTraceLogUnknownTransport(t *testing.T) {
+	cfg := testConfig()
+	cfg.TraceLog.Serial.Type = "carrierpigeon"
+	if _, err := CreateTraceLog("", 0, "", cfg); err == nil {
+		t.Errorf("Expected error on unknown serial type")
+	}
+}
+
+func collectWithFakeDUT(t *testing.T, d *fakeDUT, tr Transport, blob []byte) error {
+	tl, err := CreateTraceLog("", 0, "", testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tl.SetTransport(tr)
+	_, err = tl.CollectNewTracelog(blob)
+	tr.Close()
+	<-d.done
+	return err
+}
+
+func TestConfigUploadLarge(t *testing.T) {
+	blob := make([]byte, 10000)
+	for i := range blob {
+		blob[i] = byte(i * 7)
+	}
+	d, tr := newFakeDUT([]string{"#B! 000f0001 i O 00000080 0000ddaa 16", "#B! 000f0004 i O 00000080 0000aadd 16"})
+	// Every retry must be sent to the same offset
+	d.corrupt = 2
+
+	if err := collectWithFakeDUT(t, d, tr, blob); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.config, blob) {
+		t.Errorf("DUT received wrong config")
+	}
+}
+
+func TestConfigUploadCorrupt(t *testing.T) {
+	d, tr := newFakeDUT(nil)
+	d.corrupt = configChunkRetries
+
+	err := collectWithFakeDUT(t, d, tr, []byte{1, 2, 3})
+	if err == nil || !strings.Contains(err.Error(), "Config upload failed") {
+		t.Errorf("Expected config upload error, got %v", err)
+	}
+}
+
+func TestConfigUploadLostAck(t *testing.T) {
+	blob := make([]byte, 3*configChunkSize)
+	for i := range blob {
+		blob[i] = byte(i * 3)
+	}
+	d, tr := newFakeDUT([]string{"#B! 000f0001 i O 00000080 0000ddaa 16", "#B! 000f0004 i O 00000080 0000aadd 16"})
+	// A lost acknowledge is resent instead of switching to legacy mode
+	d.dropAcks = 1
+
+	if err := collectWithFakeDUT(t, d, tr, blob); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.config, blob) {
+		t.Errorf("DUT received wrong config")
+	}
+
+	d, tr = newFakeDUT(nil)
+	d.dropAcks = configChunkRetries
+	err := collectWithFakeDUT(t, d, tr, blob)
+	if err == nil || !strings.Contains(err.Error(), "missing acknowledge") {
+		t.Errorf("Expected missing acknowledge error, got %v", err)
+	}
+}
+
+func TestConfigUploadLegacy(t *testing.T) {
+	d, tr := newFakeDUT(nil)
+	d.legacy = true
+
+	err := collectWithFakeDUT(t, d, tr, make([]byte, legacyConfigLimit+1))
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("Expected config size error, got %v", err)
+	}
+}
+
+func TestCollectBinaryTrace(t *testing.T) {
+	trace := []string{
+		"#B! 000f0001 i O 00000080 0000ddaa 16",
+		"#B! 000f0002 m I fed40000 000000ff 8",
+		"#B! 000f0004 i O 00000080 0000aadd 16",
+	}
+	for _, binary := range []bool{true, false} {
+		d, tr := newFakeDUT(trace)
+		d.binary = binary
+
+		cfg := testConfig()
+		cfg.TraceLog.BinaryTrace = true
+		tl, err := CreateTraceLog("", 0, "", cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tl.SetTransport(tr)
+
+		tles, err := tl.CollectNewTracelog([]byte{0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		<-d.done
+		if len(tles) != 2 || tles[0].Address != 0xfed40000 || tles[0].Value != 0xff {
+			t.Errorf("binary %v: got wrong trace %v", binary, tles)
+		}
+	}
+}
+
+func TestParseLineCPUID(t *testing.T) {
+	tle, err := ParseLine("#B! 0000f000 c I 00000007 00000001 00000000 029c6fbf 40000000 bc000400")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TraceLogEntry{IP: 0xf000, Type: int(CPUID), Inout: true, Address: 7, Value: 0,
+		Subleaf: 1, Regs: [4]uint32{0, 0x029c6fbf, 0x40000000, 0xbc000400}}
+	if *tle != want {
+		t.Errorf("Got %s, want %s", tle.String(), want.String())
+	}
+
+	// Lines without subleaf and registers are still accepted
+	tle, err = ParseLine("#B! 0000f000 c I 00000001 000906ea")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tle.Value != 0x906ea || tle.Regs[0] != 0x906ea || tle.Subleaf != 0 {
+		t.Errorf("Wrong legacy CPUID entry %s", tle.String())
+	}
+
+	if _, err := ParseLine("#B! 0000f000 c I 00000007 00000000 00000001 00000002"); err == nil {
+		t.Errorf("Expected error for missing registers")
+	}
+}
+
+func TestParseLineUnsigned(t *testing.T) {
+	tests := []struct {
+		line string
+		want TraceLogEntry
+	}{
+		{"#B! fffffff0 m I fed40000 ffffffff 32",
+			TraceLogEntry{IP: 0xfffffff0, Type: int(MEM32), Inout: true, Address: 0xfed40000, Value: 0xffffffff, AccessSize: 32}},
+		{"#B! ffffffff81000000 M O 0000004000000000 8000000000000001 64",
+			TraceLogEntry{IP: 0xffffffff81000000, Type: int(MEM64), Address: 0x4000000000, Value: 0x8000000000000001, AccessSize: 64}},
+		{"#B! 0000f000 r I 000001f0 00007c00 00000100 16",
+			TraceLogEntry{IP: 0xf000, Type: int(STRIO), Inout: true, Address: 0x1f0, Value: 0x7c00, Count: 256, AccessSize: 16}},
+		{"#B! 0000f000 s I 000001b0 ffffffff ffffffff",
+			TraceLogEntry{IP: 0xf000, Type: int(MSR), Inout: true, Address: 0x1b0, Value: 0xffffffffffffffff}},
+	}
+	for _, tt := range tests {
+		tle, err := ParseLine(tt.line)
+		if err != nil {
+			t.Errorf("%s: %v", tt.line, err)
+			continue
+		}
+		if *tle != tt.want {
+			t.Errorf("Got %s, want %s", tle.String(), tt.want.String())
+		}
+	}
+
+	for _, line := range []string{
+		"#B! 0000f000 M I 00001000 00000000 24",
+		"#B! 0000f000 r O 000003f8 00001000 10",
+		"#B! 0000f000 m I -0001000 00000000 8",
+	} {
+		if _, err := ParseLine(line); err == nil {
+			t.Errorf("Expected error for %s", line)
+		}
+	}
+}
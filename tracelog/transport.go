@@ -0,0 +1,98 @@
+package tracelog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/9elements/autorev/config"
+)
+
+// Transport - A byte stream link to the autorev shell running on the DUT
+type Transport interface {
+	// Open - Opens the link. Waits up to timeout seconds for the device to appear
+	Open(timeout uint) error
+	// Read - Reads up to len(b) bytes. Blocks until at least one byte is available
+	Read(b []byte) (int, error)
+	// Write - Writes b to the DUT
+	Write(b []byte) (int, error)
+	// Close - Closes the link. It might be opened again later
+	Close() error
+}
+
+// TransportFactory - Creates a new, not yet opened, Transport from config
+type TransportFactory func(cfg config.Config) (Transport, error)
+
+var transports = map[string]TransportFactory{}
+
+// RegisterTransport - Makes a transport available as serial type name
+func RegisterTransport(name string, factory TransportFactory) {
+	transports[name] = factory
+}
+
+// NewTransport - Creates a new Transport for the serial type given in config
+func NewTransport(cfg config.Config) (Transport, error) {
+	factory, ok := transports[cfg.TraceLog.Serial.Type]
+	if !ok {
+		return nil, fmt.Errorf("Serial type '%s' is unknown (must be one of '%s')",
+			cfg.TraceLog.Serial.Type, strings.Join(TransportNames(), "', '"))
+	}
+	return factory(cfg)
+}
+
+// TransportNames - Returns the sorted names of all registered transports
+func TransportNames() []string {
+	var names []string
+	for k := range transports {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lineSettings - Serial line settings shared by all UART like transports
+type lineSettings struct {
+	BaudRate int
+	DataBits int
+	// none, odd, even, mark, space
+	Parity string
+	// 1, 1.5, 2
+	StopBits string
+}
+
+// getLineSettings - Validates the serial line settings in config and applies defaults (115200 8N1)
+func getLineSettings(cfg config.Config) (lineSettings, error) {
+	ls := lineSettings{
+		BaudRate: cfg.TraceLog.Serial.BaudRate,
+		DataBits: int(cfg.TraceLog.Serial.DataBits),
+		Parity:   strings.ToLower(cfg.TraceLog.Serial.Parity),
+		StopBits: cfg.TraceLog.Serial.StopBits,
+	}
+	if ls.BaudRate == 0 {
+		ls.BaudRate = 115200
+	}
+	if ls.DataBits == 0 {
+		ls.DataBits = 8
+	}
+	if ls.Parity == "" {
+		ls.Parity = "none"
+	}
+	if ls.StopBits == "" {
+		ls.StopBits = "1"
+	}
+
+	if ls.DataBits < 5 || ls.DataBits > 8 {
+		return ls, fmt.Errorf("Invalid serial databits %d (must be 5 to 8)", ls.DataBits)
+	}
+	switch ls.Parity {
+	case "none", "odd", "even", "mark", "space":
+	default:
+		return ls, fmt.Errorf("Invalid serial parity '%s'", ls.Parity)
+	}
+	switch ls.StopBits {
+	case "1", "1.5", "2":
+	default:
+		return ls, fmt.Errorf("Invalid serial stopbits '%s'", ls.StopBits)
+	}
+	return ls, nil
+}
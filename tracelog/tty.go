@@ -0,0 +1,90 @@
+package tracelog
+
+import (
+	"fmt"
+	"time"
+
+	"go.bug.st/serial.v1"
+
+	"github.com/9elements/autorev/config"
+)
+
+// ttyTransport - A local serial device, like /dev/ttyUSB0
+type ttyTransport struct {
+	port string
+	mode serial.Mode
+	conn serial.Port
+}
+
+func init() {
+	RegisterTransport("tty", newTTYTransport)
+}
+
+func newTTYTransport(cfg config.Config) (Transport, error) {
+	ls, err := getLineSettings(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := serial.Mode{
+		BaudRate: ls.BaudRate,
+		DataBits: ls.DataBits,
+		Parity:   serial.NoParity,
+		StopBits: serial.OneStopBit,
+	}
+	switch ls.Parity {
+	case "odd":
+		mode.Parity = serial.OddParity
+	case "even":
+		mode.Parity = serial.EvenParity
+	case "mark":
+		mode.Parity = serial.MarkParity
+	case "space":
+		mode.Parity = serial.SpaceParity
+	}
+	switch ls.StopBits {
+	case "1.5":
+		mode.StopBits = serial.OnePointFiveStopBits
+	case "2":
+		mode.StopBits = serial.TwoStopBits
+	}
+
+	return &ttyTransport{
+		port: cfg.TraceLog.Serial.Port,
+		mode: mode,
+	}, nil
+}
+
+// Open - Poll on the serial device to appear
+func (t *ttyTransport) Open(timeout uint) error {
+	var err error
+	n := time.Now()
+	limit := time.Duration(timeout) * time.Second
+
+	for time.Since(n) < limit {
+		t.conn, err = serial.Open(t.port, &t.mode)
+		if err == nil {
+			return nil
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.conn = nil
+	return fmt.Errorf("Timeout waiting for serial device")
+}
+
+func (t *ttyTransport) Read(b []byte) (int, error) {
+	return t.conn.Read(b)
+}
+
+func (t *ttyTransport) Write(b []byte) (int, error) {
+	return t.conn.Write(b)
+}
+
+func (t *ttyTransport) Close() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
@@ -0,0 +1,106 @@
+package tracelog
+
+import (
+	"fmt"
+	"hash/crc32"
+	"log"
+	"strings"
+)
+
+const (
+	// configChunkSize - Bytes sent with a single BL_CONFIG command
+	configChunkSize = 512
+	// configChunkRetries - Attempts to send a chunk before giving up
+	configChunkRetries = 3
+	// legacyConfigLimit - Shells without checksum support only accept that much
+	legacyConfigLimit = 4096
+)
+
+// configChunkCommand - Encode a chunk of config as BL_CONFIG shell command
+func configChunkCommand(chunk []byte, offset int) string {
+	var sb strings.Builder
+	sb.WriteString("3: ")
+	sb.WriteString(fmt.Sprintf("%08x", len(chunk)))
+	sb.WriteString(fmt.Sprintf("%08x", offset))
+	for i := range chunk {
+		sb.WriteString(fmt.Sprintf("%02x", chunk[i]))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// parseConfigAck - Parses the shell's "#B= OFFSET LENGTH CRC32" reply to BL_CONFIG
+// Returns false if the reply doesn't contain an acknowledge at all
+func parseConfigAck(reply string) (offset, length int, crc uint32, found bool, err error) {
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "#B= ") {
+			continue
+		}
+		_, err = fmt.Sscanf(line, "#B= %x %x %x", &offset, &length, &crc)
+		return offset, length, crc, true, err
+	}
+	return 0, 0, 0, false, nil
+}
+
+// uploadConfig - Send the config in chunks. Every chunk is verified using the
+// checksum echoed by the shell and resent on mismatch.
+// Only a shell not acknowledging the first chunk is a legacy shell, a missing
+// acknowledge later on is a corrupted reply and the chunk is resent.
+func (tl *TraceLog) uploadConfig(config []byte) error {
+	legacy := false
+	acked := false
+
+	for offset := 0; offset < len(config); offset += configChunkSize {
+		end := offset + configChunkSize
+		if end > len(config) {
+			end = len(config)
+		}
+		chunk := config[offset:end]
+		want := crc32.ChecksumIEEE(chunk)
+
+		var lastErr error
+		attempt := 0
+		for ; attempt < configChunkRetries; attempt++ {
+			reply, err := tl.shellCommand(configChunkCommand(chunk, offset))
+			if err != nil {
+				return fmt.Errorf("Config upload failed at offset 0x%x: %v", offset, err)
+			}
+
+			ackOffset, ackLength, ackCRC, found, err := parseConfigAck(reply)
+			if legacy {
+				break
+			}
+			if !found && !acked && offset == 0 {
+				// Shell doesn't echo checksums, we can't verify anything
+				legacy = true
+				lastErr = nil
+				break
+			}
+			if !found {
+				lastErr = fmt.Errorf("missing acknowledge")
+			} else if err != nil {
+				lastErr = fmt.Errorf("malformed acknowledge: %v", err)
+			} else if ackOffset != offset || ackLength != len(chunk) || ackCRC != want {
+				lastErr = fmt.Errorf("DUT acknowledged offset 0x%x length 0x%x crc %08x, expected offset 0x%x length 0x%x crc %08x",
+					ackOffset, ackLength, ackCRC, offset, len(chunk), want)
+			} else {
+				acked = true
+				lastErr = nil
+				break
+			}
+			log.Printf("Config chunk at offset 0x%x: %v, retrying\n", offset, lastErr)
+		}
+		if lastErr != nil {
+			return fmt.Errorf("Config upload failed at offset 0x%x after %d attempts: %v", offset, attempt, lastErr)
+		}
+		if legacy && end > legacyConfigLimit {
+			return fmt.Errorf("Config upload failed: shell doesn't acknowledge chunks and config (%d bytes) exceeds %d bytes", len(config), legacyConfigLimit)
+		}
+	}
+	if legacy {
+		log.Printf("Warning: shell doesn't acknowledge config chunks, upload not verified\n")
+	}
+
+	return nil
+}
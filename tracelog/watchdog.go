@@ -0,0 +1,183 @@
+package tracelog
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// FailureReason - Classifies why collecting a trace failed
+type FailureReason string
+
+const (
+	// FailureNone - The attempt was successful
+	FailureNone FailureReason = ""
+	// FailureNoConnection - The serial link couldn't be opened
+	FailureNoConnection FailureReason = "no connection"
+	// FailureNoShell - The autorev shell didn't show up
+	FailureNoShell FailureReason = "no shell"
+	// FailureConfigUpload - The config couldn't be uploaded
+	FailureConfigUpload FailureReason = "config upload failed"
+	// FailureShellCommand - A fake, filter or format command failed
+	FailureShellCommand FailureReason = "shell command failed"
+	// FailureNoStartSignal - The DUT stopped sending before the start signal
+	FailureNoStartSignal FailureReason = "no start signal"
+	// FailureHang - The DUT stopped sending after the start signal
+	FailureHang FailureReason = "hang"
+	// FailureTimeout - The wall-clock watchdog expired
+	FailureTimeout FailureReason = "timeout"
+	// FailureDutControl - The command starting the DUT failed
+	FailureDutControl FailureReason = "dut control failed"
+)
+
+// TraceError - Error returned by CollectNewTracelog
+type TraceError struct {
+	Reason FailureReason
+	// Number of entries captured before the failure
+	Entries int
+	Err     error
+}
+
+func (e *TraceError) Error() string {
+	if e.Reason == FailureHang || e.Reason == FailureTimeout {
+		return fmt.Sprintf("%s after %d entries: %v", e.Reason, e.Entries, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Reason, e.Err)
+}
+
+func (e *TraceError) Unwrap() error {
+	return e.Err
+}
+
+// GetFailureReason - Returns the failure reason of an error returned by CollectNewTracelog
+func GetFailureReason(err error) FailureReason {
+	var te *TraceError
+	if errors.As(err, &te) {
+		return te.Reason
+	}
+	if err != nil {
+		return "unknown"
+	}
+	return FailureNone
+}
+
+// Attempt - A single try to collect the trace of a test
+type Attempt struct {
+	Number   int
+	Started  time.Time
+	Finished time.Time
+	Reason   FailureReason
+	Entries  int
+	Error    string
+}
+
+// Attempts - Returns the attempts made by the last CollectNewTracelog
+func (tl *TraceLog) Attempts() []Attempt {
+	return tl.attempts
+}
+
+// readTimeout - Timeout for a single char or line. The inactivity watchdog replaces
+// the serial timeout while tracing.
+func (tl *TraceLog) readTimeout() time.Duration {
+	if tl.tracing && tl.cfg.TraceLog.Watchdog.Inactivity > 0 {
+		return time.Duration(tl.cfg.TraceLog.Watchdog.Inactivity) * time.Second
+	}
+	return time.Duration(tl.cfg.TraceLog.Serial.ReadWriteTimeout) * time.Second
+}
+
+// checkWatchdog - Returns an error once the wall-clock watchdog expired
+func (tl *TraceLog) checkWatchdog(capture *Capture) error {
+	if tl.deadline.IsZero() || time.Now().Before(tl.deadline) {
+		return nil
+	}
+	entries := 0
+	if capture != nil {
+		entries = capture.Count()
+	}
+	return &TraceError{
+		Reason:  FailureTimeout,
+		Entries: entries,
+		Err:     fmt.Errorf("Test didn't finish within %d seconds", tl.cfg.TraceLog.Watchdog.Timeout),
+	}
+}
+
+// readError - Classify a read error while tracing
+func readError(capture *Capture, err error) error {
+	reason := FailureHang
+	if !capture.Started() {
+		reason = FailureNoStartSignal
+	}
+	return &TraceError{
+		Reason:  reason,
+		Entries: capture.Count(),
+		Err:     fmt.Errorf("Failed to read from DUT connection: %s", err.Error()),
+	}
+}
+
+// CollectNewTracelog - Collects a new Trace Log
+// Failed attempts are retried as configured in the watchdog section, restarting
+// the DUT in between. If a stream is set, the entries are written while they are
+// captured and nil is returned instead.
+func (tl *TraceLog) CollectNewTracelog(config []byte) ([]TraceLogEntry, error) {
+	tl.session.reset()
+	tl.attempts = nil
+	// Keep the init command run by CreateTraceLog for the first test
+	if tl.collected {
+		tl.commands = nil
+	}
+	tl.collected = true
+
+	// Pass the config blob to DUT control commands
+	f, err := os.CreateTemp("", "autorev-config-*.bin")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(config)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	tl.configPath = f.Name()
+	defer func() {
+		tl.configPath = ""
+	}()
+
+	retries := int(tl.cfg.TraceLog.Watchdog.Retries)
+
+	for i := 0; ; i++ {
+		last := i >= retries
+		attempt := Attempt{Number: i + 1, Started: time.Now()}
+		if i > 0 {
+			tl.session.note(fmt.Sprintf("autorev: attempt %d", attempt.Number))
+		}
+
+		// Entries of a failed attempt are replaced by the retry
+		tl.captured = 0
+		if tl.stream != nil {
+			tl.stream.Reset()
+		}
+		tles, err := tl.collectAttempt(config, i > 0)
+
+		// Get DUT in the off state. Keep it running if it's going to be restarted.
+		if err == nil || last {
+			// A DUT that doesn't power off is reported, the trace is still valid
+			tl.dut.Stop()
+		}
+
+		attempt.Finished = time.Now()
+		attempt.Entries = tl.captured
+		attempt.Reason = GetFailureReason(err)
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+		tl.attempts = append(tl.attempts, attempt)
+
+		if err == nil || last {
+			return tles, err
+		}
+		log.Printf("Attempt %d failed: %v. Retrying...\n", attempt.Number, err)
+	}
+}
@@ -0,0 +1,102 @@
+package tracelog
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// rebootTransport - Starts a new fake DUT with the next trace on every Open
+type rebootTransport struct {
+	traces [][]string
+	duts   []*fakeDUT
+	mutex  sync.Mutex
+	cur    *memTransport
+}
+
+func (r *rebootTransport) Open(timeout uint) error {
+	hostR, dutW := io.Pipe()
+	dutR, hostW := io.Pipe()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cur = &memTransport{r: hostR, w: hostW}
+	trace := r.traces[len(r.duts)]
+	r.duts = append(r.duts, startFakeDUT(trace, dutR, dutW))
+	return nil
+}
+
+func (r *rebootTransport) current() *memTransport {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.cur
+}
+
+func (r *rebootTransport) Read(b []byte) (int, error)  { return r.current().Read(b) }
+func (r *rebootTransport) Write(b []byte) (int, error) { return r.current().Write(b) }
+func (r *rebootTransport) Close() error                { return r.current().Close() }
+
+var watchdogTrace = []string{
+	"#B! 000f0001 i O 00000080 0000ddaa 16",
+	"#B! 000f0002 p O 00000048 00000030 8",
+	"#B! 000f0004 i O 00000080 0000aadd 16",
+}
+
+func TestCollectRetryAfterHang(t *testing.T) {
+	tr := &rebootTransport{traces: [][]string{
+		watchdogTrace[:2],
+		watchdogTrace,
+	}}
+
+	cfg := testConfig()
+	cfg.TraceLog.Watchdog.Inactivity = 1
+	cfg.TraceLog.Watchdog.Retries = 2
+	tl, err := CreateTraceLog("", 0, "", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tl.SetTransport(tr)
+
+	tles, err := tl.CollectNewTracelog([]byte{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tles) != 2 {
+		t.Errorf("Got %d entries, want 2", len(tles))
+	}
+
+	attempts := tl.Attempts()
+	if len(attempts) != 2 {
+		t.Fatalf("Got %d attempts, want 2", len(attempts))
+	}
+	if attempts[0].Reason != FailureHang || attempts[0].Entries != 1 {
+		t.Errorf("First attempt %v, want hang after 1 entry", attempts[0])
+	}
+	if attempts[1].Reason != FailureNone || attempts[1].Entries != 2 {
+		t.Errorf("Second attempt %v, want success with 2 entries", attempts[1])
+	}
+	if !strings.Contains(string(tl.CompleteLog()), "] autorev: attempt 2\n") {
+		t.Errorf("Complete log misses attempt marker:\n%s", tl.CompleteLog())
+	}
+}
+
+func TestCollectNoStartSignal(t *testing.T) {
+	tr := &rebootTransport{traces: [][]string{
+		watchdogTrace[1:2],
+	}}
+
+	tl, err := CreateTraceLog("", 0, "", testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tl.SetTransport(tr)
+
+	_, err = tl.CollectNewTracelog([]byte{1, 2, 3})
+	if GetFailureReason(err) != FailureNoStartSignal {
+		t.Errorf("Got error %v, want %s", err, FailureNoStartSignal)
+	}
+	if len(tl.Attempts()) != 1 {
+		t.Errorf("Got %d attempts, want 1", len(tl.Attempts()))
+	}
+}